@@ -0,0 +1,221 @@
+// Package accent adds stress marks to russian text.
+package accent
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/openrussian"
+)
+
+const stressMark = '\u0301'
+
+type Status uint8
+
+const (
+	// None is used for anything that is not a russian word.
+	None Status = iota
+	// Known words have a single possible stress.
+	Known
+	// Ambiguous words can be stressed in more than one way, e.g.: замок.
+	Ambiguous
+	// Unknown words were not found in the dictionary.
+	Unknown
+)
+
+func (s Status) String() string {
+	switch s {
+	case Known:
+		return "known"
+	case Ambiguous:
+		return "ambiguous"
+	case Unknown:
+		return "unknown"
+	}
+	return ""
+}
+
+// Candidate is a possible stress of a token and the words it belongs to.
+type Candidate struct {
+	Stressed string
+	Words    []*openrussian.Word
+}
+
+type Token struct {
+	Text   string
+	Status Status
+	// Stressed is Text with a stress mark if Status is Known.
+	Stressed   string
+	Candidates []*Candidate
+}
+
+func isWordRune(r rune) bool {
+	return r == stressMark || (unicode.IsLetter(r) && unicode.Is(unicode.Cyrillic, r))
+}
+
+// IsWord reports whether token, as returned by Tokenize, is a russian word.
+func IsWord(token string) bool {
+	for _, r := range token {
+		return isWordRune(r)
+	}
+	return false
+}
+
+// Tokenize splits text in russian words (including hyphenated words) and
+// everything in between.
+func Tokenize(text string) []string {
+	runes := []rune(text)
+	tokens := make([]string, 0, len(runes)/4)
+	start := 0
+	inWord := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		word := isWordRune(r)
+		if r == '-' && inWord && i+1 < len(runes) && isWordRune(runes[i+1]) {
+			word = true
+		}
+		if word != inWord && i != start {
+			tokens = append(tokens, string(runes[start:i]))
+			start = i
+		}
+		inWord = word
+	}
+	if start < len(runes) {
+		tokens = append(tokens, string(runes[start:]))
+	}
+	return tokens
+}
+
+// stress returns the rune index of the stressed vowel in the unstressed
+// form or -1 if unknown.
+func stress(s openrussian.Stressed) int {
+	p := s.Parse()
+	if len(p) != 1 {
+		return -1
+	}
+	if p[0].Stress != "" {
+		return len([]rune(p[0].Prefix))
+	}
+	for i, r := range []rune(strings.ToLower(p[0].Prefix)) {
+		if r == 'ё' {
+			return i
+		}
+	}
+	return -1
+}
+
+func vowels(s []rune) int {
+	n := 0
+	for _, r := range s {
+		if strings.ContainsRune("аеёиоуыэюя", r) {
+			n++
+		}
+	}
+	return n
+}
+
+// apply puts the stress (and ё) of form on text, words with a single vowel
+// don't get a stress mark.
+func apply(text string, form openrussian.Stressed) string {
+	ix := stress(form)
+	f := []rune(strings.ToLower(form.Unstressed()))
+	if vowels(f) < 2 {
+		ix = -1
+	}
+	t := []rune(openrussian.Stressed(text).Unstressed())
+	n := make([]rune, 0, len(t)+1)
+	for i, r := range t {
+		yo := i < len(f) && f[i] == 'ё'
+		switch {
+		case yo && r == 'е':
+			r = 'ё'
+		case yo && r == 'Е':
+			r = 'Ё'
+		}
+		n = append(n, r)
+		if i == ix && !yo {
+			n = append(n, stressMark)
+		}
+	}
+	return string(n)
+}
+
+func candidates(text string, forms []dict.Form) []*Candidate {
+	stressed := make([]dict.Form, 0, len(forms))
+	for _, f := range forms {
+		if stress(f.Stressed) >= 0 {
+			stressed = append(stressed, f)
+		}
+	}
+	if len(stressed) != 0 {
+		forms = stressed
+	}
+
+	m := make(map[string]*Candidate, 1)
+	l := make([]*Candidate, 0, 1)
+	for _, f := range forms {
+		s := apply(text, f.Stressed)
+		c, ok := m[s]
+		if !ok {
+			c = &Candidate{Stressed: s}
+			m[s] = c
+			l = append(l, c)
+		}
+		dup := false
+		for _, w := range c.Words {
+			if w == f.Word {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			c.Words = append(c.Words, f.Word)
+		}
+	}
+
+	sort.SliceStable(l, func(i, j int) bool { return l[i].Stressed < l[j].Stressed })
+	return l
+}
+
+func annotate(d *dict.Dict, text string) Token {
+	t := Token{Text: text, Status: Unknown}
+	t.Candidates = candidates(text, d.LookupForm(text))
+	switch len(t.Candidates) {
+	case 0:
+	case 1:
+		t.Status = Known
+		t.Stressed = t.Candidates[0].Stressed
+	default:
+		t.Status = Ambiguous
+	}
+	return t
+}
+
+// Annotate tokenizes text and resolves the stress of every russian word.
+func Annotate(d *dict.Dict, text string) []Token {
+	d.InitFormIndex()
+	tokens := make([]Token, 0)
+	for _, s := range Tokenize(text) {
+		if !IsWord(s) {
+			tokens = append(tokens, Token{Text: s})
+			continue
+		}
+
+		t := annotate(d, s)
+		if t.Status != Unknown || !strings.Contains(s, "-") {
+			tokens = append(tokens, t)
+			continue
+		}
+
+		for i, part := range strings.Split(s, "-") {
+			if i != 0 {
+				tokens = append(tokens, Token{Text: "-"})
+			}
+			tokens = append(tokens, annotate(d, part))
+		}
+	}
+
+	return tokens
+}
@@ -0,0 +1,48 @@
+package accent
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		exp  []string
+	}{
+		{"Это мой дом.", []string{"Это", " ", "мой", " ", "дом", "."}},
+		{"кто-то пришёл", []string{"кто-то", " ", "пришёл"}},
+		{"- да -", []string{"- ", "да", " -"}},
+		{"сто́л, 2 abc", []string{"сто́л", ", 2 abc"}},
+	}
+
+	for _, test := range tests {
+		if got := Tokenize(test.text); !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("Tokenize(%q): exp: %q got: %q", test.text, test.exp, got)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		text string
+		form openrussian.Stressed
+		exp  string
+	}{
+		{"Замок", "за'мок", "За́мок"},
+		{"Замок", "за́мок", "За́мок"},
+		{"замок", "замо'к", "замо́к"},
+		{"все", "всё", "всё"},
+		{"ВСЕ", "всё", "ВСЁ"},
+		{"в", "в", "в"},
+		{"Стол", "сто'л", "Стол"},
+	}
+
+	for _, test := range tests {
+		if got := apply(test.text, test.form); got != test.exp {
+			t.Errorf("apply(%q, %q): exp: %q got: %q", test.text, test.form, test.exp, got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package accent
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+// Formats are the supported output formats.
+var Formats = []string{"plain", "html", "json"}
+
+// Write writes the tokens in one of Formats.
+func Write(w io.Writer, format string, tokens []Token) error {
+	switch format {
+	case "plain":
+		return Plain(w, tokens)
+	case "html":
+		return HTML(w, tokens)
+	case "json":
+		return JSON(w, tokens)
+	}
+	return fmt.Errorf("unknown format '%s', available: %s", format, strings.Join(Formats, ", "))
+}
+
+func candidateList(t Token) []string {
+	l := make([]string, len(t.Candidates))
+	for i, c := range t.Candidates {
+		l[i] = c.Stressed
+	}
+	return l
+}
+
+// Plain writes the text with U+0301 stress marks, ambiguous words are
+// followed by their options between brackets: замок[за́мок|замо́к].
+func Plain(w io.Writer, tokens []Token) error {
+	var b strings.Builder
+	for _, t := range tokens {
+		switch t.Status {
+		case Known:
+			b.WriteString(t.Stressed)
+		case Ambiguous:
+			b.WriteString(t.Text)
+			b.WriteString("[")
+			b.WriteString(strings.Join(candidateList(t), "|"))
+			b.WriteString("]")
+		default:
+			b.WriteString(t.Text)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// HTML writes every word as a span with the class known, ambiguous or
+// unknown, ambiguous words list their options in the title attribute.
+func HTML(w io.Writer, tokens []Token) error {
+	var b strings.Builder
+	for _, t := range tokens {
+		switch t.Status {
+		case None:
+			b.WriteString(strings.ReplaceAll(html.EscapeString(t.Text), "\n", "<br/>\n"))
+		case Known:
+			fmt.Fprintf(&b, `<span class="known">%s</span>`, html.EscapeString(t.Stressed))
+		case Ambiguous:
+			fmt.Fprintf(
+				&b,
+				`<span class="ambiguous" title="%s">%s</span>`,
+				html.EscapeString(strings.Join(candidateList(t), ", ")),
+				html.EscapeString(t.Text),
+			)
+		case Unknown:
+			fmt.Fprintf(&b, `<span class="unknown">%s</span>`, html.EscapeString(t.Text))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type jsonCandidate struct {
+	Stressed string           `json:"stressed"`
+	IDs      []openrussian.ID `json:"ids"`
+}
+
+type jsonToken struct {
+	Text       string          `json:"text"`
+	Status     string          `json:"status,omitempty"`
+	Stressed   string          `json:"stressed,omitempty"`
+	Candidates []jsonCandidate `json:"candidates,omitempty"`
+}
+
+// JSON writes an array of tokens. Words have a status (known, ambiguous or
+// unknown), known words have a stressed field and every candidate lists
+// the ids of the words it belongs to.
+func JSON(w io.Writer, tokens []Token) error {
+	l := make([]jsonToken, len(tokens))
+	for i, t := range tokens {
+		l[i] = jsonToken{Text: t.Text, Status: t.Status.String(), Stressed: t.Stressed}
+		for _, c := range t.Candidates {
+			jc := jsonCandidate{Stressed: c.Stressed, IDs: make([]openrussian.ID, len(c.Words))}
+			for j, w := range c.Words {
+				jc.IDs[j] = w.ID
+			}
+			l[i].Candidates = append(l[i].Candidates, jc)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(l)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/export"
+	"github.com/frizinak/goru/export/anki"
+	"github.com/frizinak/goru/export/sqlite"
+	"github.com/frizinak/goru/openrussian"
+)
+
+func exit(err error) {
+	if err == nil {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+func parseFilter(levels, types, rank, list string) (dict.Filter, error) {
+	var f dict.Filter
+	var err error
+	if f.Levels, err = dict.ParseLevels(levels); err != nil {
+		return f, err
+	}
+	if f.Types, err = dict.ParseTypes(types); err != nil {
+		return f, err
+	}
+
+	if rank != "" {
+		p := strings.SplitN(rank, ":", 2)
+		if p[0] != "" {
+			if f.MinRank, err = strconv.ParseUint(p[0], 10, 64); err != nil {
+				return f, fmt.Errorf("invalid rank range '%s'", rank)
+			}
+		}
+		if len(p) == 2 && p[1] != "" {
+			if f.MaxRank, err = strconv.ParseUint(p[1], 10, 64); err != nil {
+				return f, fmt.Errorf("invalid rank range '%s'", rank)
+			}
+		}
+	}
+
+	if list == "" {
+		return f, nil
+	}
+
+	var r io.Reader = os.Stdin
+	if list != "-" {
+		file, err := os.Open(list)
+		if err != nil {
+			return f, err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		if w := strings.TrimSpace(scan.Text()); w != "" {
+			f.Words = append(f.Words, w)
+		}
+	}
+
+	return f, scan.Err()
+}
+
+func main() {
+	var db string
+	var lang string
+	var format string
+	var dir string
+	var sentences bool
+	var grammar bool
+	flag.StringVar(&db, "db", "data/data/db.web.gob", "database to export")
+	flag.StringVar(&lang, "l", openrussian.DefaultLanguage, "translation language")
+	flag.StringVar(&format, "f", "stardict", "output format: stardict, dictd, jsonl, sqlite, anki (tsv), apkg")
+	flag.StringVar(&dir, "o", "dist/dict", "output directory")
+	flag.BoolVar(&sentences, "s", false, "include example sentences")
+	flag.BoolVar(&grammar, "g", false, "include declensions and conjugations")
+
+	var levels, types, rank, list, deck string
+	var noImages bool
+	flag.StringVar(&levels, "level", "", "only export words of these comma separated levels (e.g.: A1,A2)")
+	flag.StringVar(&types, "type", "", "only export words of these comma separated types (e.g.: noun,verb)")
+	flag.StringVar(&rank, "rank", "", "only export words within this rank range (e.g.: 1:500)")
+	flag.StringVar(&list, "words", "", "only export the words listed in this file, one per line ('-' for stdin)")
+	flag.StringVar(&deck, "deck", "goru", "anki deck name")
+	flag.BoolVar(&noImages, "no-images", false, "do not add cursive images to anki cards")
+	flag.Parse()
+
+	filter, err := parseFilter(levels, types, rank, list)
+	exit(err)
+
+	words, err := openrussian.LoadGOB(db)
+	exit(err)
+	words = filter.Filter(words)
+	exit(os.MkdirAll(dir, 0755))
+
+	switch format {
+	case "anki", "apkg":
+		cards, err := anki.NewCards(words, lang, !noImages)
+		exit(err)
+		if format == "apkg" {
+			exit(anki.APKG(filepath.Join(dir, deck+".apkg"), deck, cards))
+			return
+		}
+		media := filepath.Join(dir, "collection.media")
+		exit(os.MkdirAll(media, 0755))
+		f, err := os.Create(filepath.Join(dir, deck+".tsv"))
+		exit(err)
+		w := bufio.NewWriter(f)
+		exit(anki.TSV(w, media, cards))
+		exit(w.Flush())
+		exit(f.Close())
+		return
+	case "jsonl":
+		f, err := os.Create(filepath.Join(dir, "goru.jsonl"))
+		exit(err)
+		w := bufio.NewWriter(f)
+		exit(export.JSONL(w, words))
+		exit(w.Flush())
+		exit(f.Close())
+		return
+	case "sqlite":
+		exit(sqlite.Export(filepath.Join(dir, "goru.sqlite"), words))
+		return
+	}
+
+	info := export.Info{
+		Name:        fmt.Sprintf("goru-ru-%s", lang),
+		Title:       fmt.Sprintf("goru Russian-%s", strings.ToUpper(lang)),
+		Description: "Russian dictionary based on openrussian.org data (CC BY-SA 4.0)",
+		URL:         "https://en.openrussian.org",
+	}
+
+	custom := `{{- define "gender" -}}{{ . }}{{- end -}}`
+	var detail string
+	if sentences {
+		detail += `{{ template "sentences" . }}`
+	}
+	if grammar {
+		detail += `{{ template "grammar" . }}`
+	}
+	if detail != "" {
+		custom += `{{- define "wordDetail" }}` + detail + `{{ end -}}`
+	}
+	masterTpl, err := common.GetPlainTpl()
+	exit(err)
+	tpl, err := masterTpl.Clone()
+	exit(err)
+	tpl, err = tpl.Parse(custom)
+	exit(err)
+
+	buf := bytes.NewBuffer(nil)
+	entries, err := export.NewEntries(words, func(w *openrussian.Word) (string, error) {
+		buf.Reset()
+		err := tpl.ExecuteTemplate(buf, "word", w.Localized(lang))
+		return buf.String(), err
+	})
+	exit(err)
+
+	switch format {
+	case "stardict":
+		exit(export.StarDict(dir, info, entries))
+	case "dictd":
+		exit(export.Dictd(dir, info, entries))
+	default:
+		exit(errors.New("unknown format"))
+	}
+}
@@ -15,6 +15,9 @@ func main() {
 	var decl openrussian.CSVDeclensions
 	var verbs openrussian.CSVVerbs
 	var conjs openrussian.CSVConjugations
+	var sentences openrussian.CSVSentences
+	var sentenceTrans openrussian.CSVSentenceTranslations
+	var sentenceWords openrussian.CSVSentenceWords
 
 	x := []struct {
 		f  string
@@ -76,6 +79,30 @@ func main() {
 				return err
 			},
 		},
+		{
+			f: "temp/sentences.csv",
+			cb: func(r io.Reader) error {
+				var err error
+				sentences, err = openrussian.DecodeSentences(r)
+				return err
+			},
+		},
+		{
+			f: "temp/sentences_translations.csv",
+			cb: func(r io.Reader) error {
+				var err error
+				sentenceTrans, err = openrussian.DecodeSentenceTranslations(r)
+				return err
+			},
+		},
+		{
+			f: "temp/sentences_words.csv",
+			cb: func(r io.Reader) error {
+				var err error
+				sentenceWords, err = openrussian.DecodeSentenceWords(r)
+				return err
+			},
+		},
 	}
 
 	gob := "data/data/db.gob"
@@ -95,12 +122,14 @@ func main() {
 		}()
 	}
 
-	all := openrussian.Merge(words, trans, nouns, nil, nil, nil, nil)
+	all := openrussian.Merge(words, trans, nouns, adj, nil, nil, nil)
+	openrussian.MergeSentences(all, sentences, sentenceTrans, sentenceWords)
 	if err := openrussian.StoreGOB(gob, all); err != nil {
 		panic(err)
 	}
 
 	all = openrussian.Merge(words, trans, nouns, adj, decl, verbs, conjs)
+	openrussian.MergeSentences(all, sentences, sentenceTrans, sentenceWords)
 	if err := openrussian.StoreGOB(gobweb, all); err != nil {
 		panic(err)
 	}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/frizinak/goru/accent"
+)
+
+func readText(files []string) (string, error) {
+	if len(files) == 0 {
+		d, err := io.ReadAll(os.Stdin)
+		return string(d), err
+	}
+
+	var b strings.Builder
+	for _, file := range files {
+		d, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		b.Write(d)
+	}
+	return b.String(), nil
+}
+
+func cmdAccent(args []string) error {
+	fs := flag.NewFlagSet("accent", flag.ExitOnError)
+	var format string
+	fs.StringVar(&format, "o", "plain", fmt.Sprintf("output format: %s", strings.Join(accent.Formats, ", ")))
+	fs.Usage = usage(fs, "[file...]")
+	fs.Parse(args)
+
+	text, err := readText(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	d, err := getDetailDict(conf.Language)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	if err := accent.Write(w, format, accent.Annotate(d, text)); err != nil {
+		return err
+	}
+	return w.Flush()
+}
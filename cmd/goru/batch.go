@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/export"
+)
+
+type batchJob struct {
+	query string
+	done  chan batchResult
+}
+
+type batchResult struct {
+	results dict.Results
+	fuzzy   bool
+}
+
+func readQueries(files []string, cb func(query string)) error {
+	read := func(r io.Reader) error {
+		scan := bufio.NewScanner(r)
+		for scan.Scan() {
+			if q := strings.TrimSpace(scan.Text()); q != "" {
+				cb(q)
+			}
+		}
+		return scan.Err()
+	}
+
+	if len(files) == 0 {
+		return read(os.Stdin)
+	}
+
+	for _, file := range files {
+		if file == "-" {
+			if err := read(os.Stdin); err != nil {
+				return err
+			}
+			continue
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = read(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// batch looks up every line of the given files (or stdin) in parallel and
+// writes the results in input order.
+func batch(w io.Writer, d *dict.Dict, o options, files []string) error {
+	tpl, err := o.template()
+	if err != nil {
+		return err
+	}
+
+	var lw *export.LookupWriter
+	if o.output != "text" {
+		if lw, err = export.NewLookupWriter(w, o.output); err != nil {
+			return err
+		}
+	}
+
+	d.InitRussianFuzzIndex()
+	d.InitTranslationFuzzIndex(o.lang)
+
+	workers := runtime.NumCPU()
+	jobs := make(chan *batchJob, workers)
+	order := make(chan *batchJob, workers*4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			for j := range jobs {
+				results, fuzzy := lookup(d, o, j.query)
+				j.done <- batchResult{results, fuzzy}
+			}
+			wg.Done()
+		}()
+	}
+
+	var readErr error
+	go func() {
+		readErr = readQueries(files, func(query string) {
+			j := &batchJob{query: query, done: make(chan batchResult, 1)}
+			order <- j
+			jobs <- j
+		})
+		close(jobs)
+		close(order)
+	}()
+
+	out := bufio.NewWriter(w)
+	var writeErr error
+	for j := range order {
+		r := <-j.done
+		if writeErr != nil {
+			continue
+		}
+		if lw != nil {
+			writeErr = lw.Write(export.NewLookup(j.query, r.fuzzy, r.results))
+			continue
+		}
+
+		if _, writeErr = fmt.Fprintf(out, "> %s\n", j.query); writeErr != nil {
+			continue
+		}
+		if len(r.results) == 0 {
+			_, writeErr = fmt.Fprintf(out, "  %s\n\n", errNoResults)
+			continue
+		}
+		writeErr = o.writeWords(out, tpl, r.results.Words())
+	}
+	wg.Wait()
+
+	if readErr != nil {
+		return readErr
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+	if lw != nil {
+		return lw.Close()
+	}
+	return out.Flush()
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/export"
+	"github.com/frizinak/goru/openrussian"
+)
+
+// find looks up query and only keeps words that match the given filter.
+func find(d *dict.Dict, o options, query string, match func(*openrussian.Word) bool) []*openrussian.Word {
+	o.all = true
+	o.maxResults = 10
+	res, _ := lookup(d, o, query)
+	n := make([]*openrussian.Word, 0, len(res))
+	for _, w := range res.Words() {
+		if match(w) {
+			n = append(n, w)
+		}
+	}
+	if len(n) == 0 {
+		return n
+	}
+
+	// Prefer exact matches (e.g.: both за́мок and замо́к) over fuzzy ones.
+	exact := make([]*openrussian.Word, 0, len(n))
+	for _, w := range n {
+		if strings.EqualFold(w.Word, query) {
+			exact = append(exact, w)
+		}
+	}
+	if len(exact) != 0 {
+		return exact
+	}
+	return n[:1]
+}
+
+func queryArgs(fs *flag.FlagSet) (string, error) {
+	query := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if query == "" {
+		return "", errors.New("please provide a word")
+	}
+	return query, nil
+}
+
+func writeTable(w io.Writer, t common.Table) error {
+	if len(t) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+	return t.Write(w, "  ", termWidth)
+}
+
+func writeHeader(w io.Writer, f *former, word *openrussian.Word) error {
+	s := f.form(word.Stressed)
+	if word.NounInfo != nil {
+		s += " " + word.NounInfo.Gender.String()
+	}
+	if word.VerbInfo != nil && word.VerbInfo.Aspect != 0 {
+		s += " " + word.VerbInfo.Aspect.String()
+	}
+	s += " " + word.WordType.String()
+	tl := make([]string, len(word.Translations))
+	for i, t := range word.Translations {
+		tl[i] = t.Translation
+	}
+	if len(tl) != 0 {
+		s += "\n  " + strings.Join(tl, "; ")
+	}
+	_, err := fmt.Fprintln(w, s)
+	return err
+}
+
+func cmdTables(
+	name, desc string,
+	args []string,
+	match func(*openrussian.Word) bool,
+	tplName string,
+) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	var o options
+	fs.StringVar(&o.lang, "l", conf.Language, "translation language")
+	fs.BoolVar(&o.noStress, "ns", false, "don't print stress mark")
+	o.colorFlags(fs)
+	fs.Usage = usage(fs, "<word>")
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+	query, err := queryArgs(fs)
+	if err != nil {
+		return err
+	}
+
+	d, err := getDetailDict(o.lang)
+	if err != nil {
+		return err
+	}
+
+	words := find(d, o, query, match)
+	if len(words) == 0 {
+		return fmt.Errorf("no %s found for '%s'", desc, query)
+	}
+
+	tpl, err := o.template()
+	if err != nil {
+		return err
+	}
+
+	f := o.former()
+	for i, w := range words {
+		if i != 0 {
+			fmt.Println()
+		}
+		if err := writeHeader(os.Stdout, f, w); err != nil {
+			return err
+		}
+		if err := tpl.ExecuteTemplate(os.Stdout, tplName, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cmdDecline(args []string) error {
+	return cmdTables(
+		"decline",
+		"noun or adjective",
+		args,
+		func(w *openrussian.Word) bool { return w.NounInfo != nil || w.AdjInfo != nil },
+		"declension",
+	)
+}
+
+func cmdConjugate(args []string) error {
+	return cmdTables(
+		"conjugate",
+		"verb",
+		args,
+		func(w *openrussian.Word) bool { return w.VerbInfo != nil },
+		"conjugation",
+	)
+}
+
+func cmdInfo(args []string) error {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	var o options
+	fs.StringVar(&o.lang, "l", conf.Language, "translation language")
+	fs.BoolVar(&o.noStress, "ns", false, "don't print stress mark")
+	o.colorFlags(fs)
+	o.cursiveFlags(fs)
+	fs.Usage = usage(fs, "<id>")
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("please provide a word id")
+	}
+	id, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid id '%s'", fs.Arg(0))
+	}
+
+	d, err := getDetailDict(o.lang)
+	if err != nil {
+		return err
+	}
+
+	word, ok := d.Words()[openrussian.ID(id)]
+	if !ok {
+		return fmt.Errorf("no word with id %d", id)
+	}
+	word = word.Localized(o.lang)
+
+	o.sentences = true
+	tpl, err := o.template()
+	if err != nil {
+		return err
+	}
+	if err := tpl.ExecuteTemplate(os.Stdout, "word", word); err != nil {
+		return err
+	}
+	if o.cursive {
+		fmt.Println()
+		if err := o.drawCursive(os.Stdout, word.Word); err != nil {
+			return err
+		}
+	}
+
+	f := o.former()
+	meta := common.Table{{"id", strconv.FormatUint(uint64(word.ID), 10)}}
+	if word.Rank != 0 {
+		meta = append(meta, []string{"rank", strconv.FormatUint(word.Rank, 10)})
+	}
+	if word.LanguageLevel != 0 {
+		meta = append(meta, []string{"level", word.LanguageLevel.String()})
+	}
+	if word.VerbInfo != nil && word.VerbInfo.Aspect != 0 {
+		meta = append(meta, []string{"aspect", word.VerbInfo.Aspect.String()})
+	}
+	if n := word.NounInfo; n != nil {
+		if n.SingularOnly {
+			meta = append(meta, []string{"number", "singular only"})
+		}
+		if n.PluralOnly {
+			meta = append(meta, []string{"number", "plural only"})
+		}
+	}
+	if l := dict.DerivedList(word); len(l) != 0 {
+		s := make([]string, len(l))
+		for i, w := range l {
+			s[i] = fmt.Sprintf("%s (%d)", f.form(w.Stressed), w.ID)
+		}
+		meta = append(meta, []string{"derived from", strings.Join(s, " > ")})
+	}
+	if seg := d.Segment(word); len(seg) != 0 {
+		meta = append(meta, []string{"morphemes", f.segmentation(seg)}, []string{"root", seg.Root()})
+	}
+	if err := writeTable(os.Stdout, meta); err != nil {
+		return err
+	}
+
+	return tpl.ExecuteTemplate(os.Stdout, "grammar", word)
+}
+
+func cmdRandom(args []string) error {
+	fs := flag.NewFlagSet("random", flag.ExitOnError)
+	var o options
+	var levels, types string
+	o.flags(fs)
+	fs.StringVar(&levels, "level", "", "comma separated levels (e.g.: A1,A2)")
+	fs.StringVar(&types, "type", "", "comma separated word types (e.g.: noun,verb)")
+	fs.Usage = usage(fs, "")
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+
+	var filter dict.Filter
+	var err error
+	if filter.Levels, err = dict.ParseLevels(levels); err != nil {
+		return err
+	}
+	if filter.Types, err = dict.ParseTypes(types); err != nil {
+		return err
+	}
+
+	d, err := o.dict()
+	if err != nil {
+		return err
+	}
+
+	words := export.SortedWords(filter.Filter(d.Words()))
+	n := 0
+	for _, w := range words {
+		if o.all || len(w.TranslationsFor(o.lang)) != 0 {
+			words[n] = w
+			n++
+		}
+	}
+	words = words[:n]
+	if len(words) == 0 {
+		return errNoResults
+	}
+
+	tpl, err := o.template()
+	if err != nil {
+		return err
+	}
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	word := words[rnd.Intn(len(words))]
+	return o.writeWords(os.Stdout, tpl, []*openrussian.Word{word.Localized(o.lang)})
+}
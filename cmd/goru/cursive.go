@@ -0,0 +1,69 @@
+package main
+
+import (
+	"image/color"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/frizinak/goru/image"
+	"github.com/frizinak/goru/openrussian"
+	"github.com/frizinak/goru/termimg"
+)
+
+const (
+	// cursiveHeight is the height in pixels of images drawn with the
+	// graphics protocols.
+	cursiveHeight = 64
+	// cursiveBlocksHeight is the height in pixels (half rows) of images drawn
+	// with half blocks.
+	cursiveBlocksHeight = 24
+)
+
+// cursiveColor guesses the terminal's foreground color from $COLORFGBG,
+// defaulting to white.
+func cursiveColor() color.NRGBA {
+	fgbg := strings.Split(os.Getenv("COLORFGBG"), ";")
+	switch fgbg[len(fgbg)-1] {
+	case "7", "15":
+		return color.NRGBA{0, 0, 0, 255}
+	}
+	return color.NRGBA{255, 255, 255, 255}
+}
+
+// drawCursive writes word in cursive using the Lobster font.
+func (o options) drawCursive(w io.Writer, word string) error {
+	height := cursiveHeight
+	if o.protocol == termimg.Blocks {
+		height = cursiveBlocksHeight
+	}
+	img, err := image.Image(height, "", word, false, cursiveColor(), color.NRGBA{})
+	if err != nil {
+		return err
+	}
+	return termimg.Write(w, img, o.protocol, termWidth-2)
+}
+
+// writeWords executes tpl for words, drawing each of them in cursive below
+// their entry if requested.
+func (o options) writeWords(w io.Writer, tpl *template.Template, words []*openrussian.Word) error {
+	if !o.cursive {
+		return tpl.Execute(w, words)
+	}
+	for _, word := range words {
+		if err := tpl.ExecuteTemplate(w, "word", word); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+		if err := o.drawCursive(w, word.Word); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
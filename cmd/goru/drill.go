@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/quiz"
+	"github.com/frizinak/goru/wordlist"
+)
+
+func cmdDrill(args []string) error {
+	fs := flag.NewFlagSet("drill", flag.ExitOnError)
+	var o options
+	var list, levels, kind, cases, persons string
+	var n int
+	o.colorFlags(fs)
+	fs.BoolVar(&o.noStress, "ns", false, "don't print stress mark")
+	fs.StringVar(&kind, "kind", "both", "decline, conjugate or both")
+	fs.StringVar(&cases, "cases", "", "comma separated cases (e.g.: inst,prep), defaults to all")
+	fs.StringVar(&persons, "persons", "", "comma separated persons (e.g.: я,мы or 1sg,1pl), defaults to all")
+	fs.StringVar(&levels, "level", "", "comma separated levels (e.g.: A1,A2)")
+	fs.StringVar(&list, "list", "", "drill the words in this list (see 'goru lists')")
+	fs.IntVar(&n, "n", 10, "amount of questions")
+	fs.Usage = func() {
+		usage(fs, "")()
+		fmt.Fprintln(fs.Output(), `
+Answer with the requested form, stress marks are optional.
+An empty answer means you don't know, :q stops the drill.`)
+	}
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+
+	do, err := quiz.ParseDrillOptions(kind, cases, persons)
+	if err != nil {
+		return err
+	}
+	var filter dict.Filter
+	if filter.Levels, err = dict.ParseLevels(levels); err != nil {
+		return err
+	}
+	if list != "" {
+		if err := wordlist.ValidName(list); err != nil {
+			return err
+		}
+	}
+
+	d, err := getDetailDict(conf.Language)
+	if err != nil {
+		return err
+	}
+	words, err := quizWords(d, conf.Language, list, filter)
+	if err != nil {
+		return err
+	}
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	drills := quiz.Drills(words, do, n, rnd)
+	if len(drills) == 0 {
+		return errors.New("no words to drill")
+	}
+
+	var green, red common.Style
+	if o.colors {
+		theme := common.CurrentTheme()
+		green, red = theme.Get("green"), theme.Get("red")
+	}
+	f := o.former()
+
+	in := bufio.NewScanner(os.Stdin)
+	var asked, correct int
+	for i, drill := range drills {
+		fmt.Printf("\n[%d/%d] %s\n> ", i+1, len(drills), drill.Prompt())
+		if !in.Scan() {
+			fmt.Println()
+			break
+		}
+		answer := strings.TrimSpace(in.Text())
+		if answer == ":q" {
+			break
+		}
+
+		asked++
+		g := drill.Check(answer)
+		switch {
+		case drill.Correct(g):
+			correct++
+			fmt.Println(green.Wrap("correct"))
+		case answer == "":
+			fmt.Println(red.Wrap("skipped"))
+		case g.Edits != nil:
+			fmt.Printf("%s %s\n", red.Wrap("wrong:"), renderEdits(g.Edits, o.colors))
+		default:
+			fmt.Println(red.Wrap("wrong"))
+		}
+		fmt.Printf("  %s\n", f.list(drill.Answers))
+	}
+
+	fmt.Printf("\n%d/%d correct\n", correct, asked)
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/openrussian"
+)
+
+// writeFamily prints f as an indented tree, marking word with an asterisk.
+func writeFamily(w io.Writer, fm *former, lang string, f *dict.Family, word *openrussian.Word) error {
+	out := bufio.NewWriter(w)
+	var walk func(f *dict.Family, prefix, branch string)
+	walk = func(f *dict.Family, prefix, branch string) {
+		l := f.Word.Localized(lang)
+		s := prefix + branch + fm.form(l.Stressed) + " " + l.WordType.String()
+		if l.ID == word.ID {
+			s += " *"
+		}
+		if tl := translation(l); tl != "" {
+			s += "  " + tl
+		}
+		if termWidth != 0 {
+			s = common.Truncate(s, termWidth)
+		}
+		out.WriteString(s)
+		out.WriteByte('\n')
+
+		switch branch {
+		case "├─ ":
+			prefix += "│  "
+		case "└─ ":
+			prefix += "   "
+		}
+		for i, d := range f.Derived {
+			b := "├─ "
+			if i == len(f.Derived)-1 {
+				b = "└─ "
+			}
+			walk(d, prefix, b)
+		}
+	}
+	walk(f, "", "")
+	return out.Flush()
+}
+
+func cmdFamily(args []string) error {
+	fs := flag.NewFlagSet("family", flag.ExitOnError)
+	var o options
+	fs.StringVar(&o.lang, "l", conf.Language, "translation language")
+	fs.BoolVar(&o.noStress, "ns", false, "don't print stress mark")
+	o.colorFlags(fs)
+	fs.Usage = usage(fs, "<word|id>")
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+	query, err := queryArgs(fs)
+	if err != nil {
+		return err
+	}
+
+	d, err := getDict(o.lang)
+	if err != nil {
+		return err
+	}
+
+	word, alt, err := resolve(d, o, query)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFamily(os.Stdout, o.former(), o.lang, d.WordFamily(word), word); err != nil {
+		return err
+	}
+	if len(alt) != 0 {
+		s := make([]string, len(alt))
+		for i, w := range alt {
+			s[i] = fmt.Sprintf("%s (%d)", w.Stressed, w.ID)
+		}
+		fmt.Printf("\nalso: %s, use the id or stressed form to pick one\n", strings.Join(s, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/frizinak/goru/glossary"
+	"github.com/frizinak/goru/openrussian"
+)
+
+func cmdGlossary(args []string) error {
+	fs := flag.NewFlagSet("glossary", flag.ExitOnError)
+	var o glossary.Options
+	var format, above, sort string
+	fs.StringVar(&o.Lang, "l", conf.Language, "translation language")
+	fs.StringVar(&format, "o", "md", fmt.Sprintf("output format: %s", strings.Join(glossary.Formats, ", ")))
+	fs.StringVar(&above, "above", "", "exclude words at or below this level (e.g.: A2)")
+	fs.StringVar(&sort, "sort", "count", "sort by: count, text or alpha")
+	fs.Usage = usage(fs, "[file...]")
+	fs.Parse(args)
+
+	if above != "" {
+		l, ok := openrussian.ParseLanguageLevel(above)
+		if !ok {
+			return fmt.Errorf("invalid level '%s'", above)
+		}
+		o.Above = l
+	}
+
+	switch sort {
+	case "count":
+		o.Sort = glossary.ByCount
+	case "text":
+		o.Sort = glossary.ByText
+	case "alpha":
+		o.Sort = glossary.ByAlpha
+	default:
+		return fmt.Errorf("invalid sort '%s'", sort)
+	}
+
+	text, err := readText(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	d, err := getDetailDict(o.Lang)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	if err := glossary.New(d, text, o).Write(w, format); err != nil {
+		return err
+	}
+	return w.Flush()
+}
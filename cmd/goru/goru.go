@@ -4,12 +4,24 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/export"
+	"github.com/frizinak/goru/termimg"
 )
 
+var errNoResults = errors.New("no results")
+
+// conf holds the flag defaults, see common.LoadConfig.
+var conf = common.DefaultConfig()
+
 func exit(err error) {
 	if err == nil {
 		return
@@ -19,42 +31,377 @@ func exit(err error) {
 	os.Exit(1)
 }
 
-func main() {
-	var maxResults uint
-	var all bool
-	var noStress bool
-	flag.UintVar(&maxResults, "n", 3, "max amount of results")
-	flag.BoolVar(&all, "a", false, "include words without translation")
-	flag.BoolVar(&noStress, "ns", false, "don't print stress mark")
-	flag.Parse()
+type options struct {
+	maxResults uint
+	all        bool
+	noStress   bool
+	lang       string
+	sentences  bool
+	grammar    bool
+	output     string
+	color      string
+	theme      string
+	history    bool
+	cursive    bool
+	graphics   string
+
+	colors   bool
+	protocol termimg.Protocol
+}
+
+func (o *options) colorFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.color, "color", conf.Color, "use colors: auto, always or never (auto honours NO_COLOR)")
+	fs.StringVar(&o.theme, "theme", conf.Theme, "color theme, see 'goru help themes'")
+}
 
-	query := strings.TrimSpace(strings.Join(flag.Args(), " "))
-	if query == "" {
-		exit(errors.New("please provide a query"))
+func (o *options) cursiveFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.cursive, "cursive", false, "draw words in cursive (only in a terminal)")
+	fs.StringVar(
+		&o.graphics,
+		"graphics",
+		"auto",
+		fmt.Sprintf("how to draw cursive: %s", strings.Join(termimg.Protocols, ", ")),
+	)
+}
+
+func (o *options) flags(fs *flag.FlagSet) {
+	fs.UintVar(&o.maxResults, "n", conf.Results, "max amount of results")
+	fs.BoolVar(&o.all, "a", false, "include words without translation")
+	fs.BoolVar(&o.noStress, "ns", false, "don't print stress mark")
+	fs.StringVar(&o.lang, "l", conf.Language, "translation language")
+	fs.BoolVar(&o.sentences, "s", false, "list example sentences")
+	fs.BoolVar(&o.grammar, "g", false, "list declensions and conjugations")
+	fs.BoolVar(&o.history, "history", conf.History, "record lookups for 'goru history'")
+	o.colorFlags(fs)
+	o.cursiveFlags(fs)
+	fs.StringVar(
+		&o.output,
+		"o",
+		"text",
+		fmt.Sprintf("output format: text, %s", strings.Join(export.ResultFormats, ", ")),
+	)
+}
+
+func themesFile() (string, error) {
+	dir, err := common.ConfigDir()
+	if err != nil {
+		return "", err
 	}
+	return filepath.Join(dir, "themes.json"), nil
+}
 
-	d, err := common.GetDict()
-	exit(err)
+// termWidth is the width of stdout or 0 if it is not a terminal.
+var termWidth int
 
+// setup decides whether stdout gets colors, activates the theme, wraps
+// the templates at the terminal width and picks how to draw cursive.
+func (o *options) setup() error {
+	termWidth = common.TermWidth(os.Stdout)
+	common.SetWidth(termWidth)
+
+	mode, err := common.ParseColorMode(o.color)
+	if err != nil {
+		return err
+	}
+	o.colors = mode.Enabled(os.Stdout)
+
+	if path, err := themesFile(); err == nil {
+		if err := common.LoadThemes(path); err != nil {
+			return err
+		}
+	}
+
+	theme, ok := common.Themes[o.theme]
+	if !ok {
+		return fmt.Errorf("unknown theme '%s', available: %s", o.theme, strings.Join(themeNames(), ", "))
+	}
+	common.SetTheme(theme)
+
+	o.cursive = o.cursive && termWidth != 0
+	o.protocol, err = termimg.ParseProtocol(o.graphics)
+	return err
+}
+
+func themeNames() []string {
+	l := make([]string, 0, len(common.Themes))
+	for name := range common.Themes {
+		l = append(l, name)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func (o options) former() *former {
+	f := &former{noStress: o.noStress}
+	if o.colors {
+		f.stress = common.CurrentTheme().Get("stress")
+	}
+	return f
+}
+
+func checkOutput(format string) error {
+	if format == "text" {
+		return nil
+	}
+	for _, f := range export.ResultFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format '%s'", format)
+}
+
+func (o options) template() (*template.Template, error) {
 	custom := `{{- define "gender" -}}{{ . }}{{- end -}}`
-	if noStress {
+	if o.noStress {
 		custom += `{{- define "wordStr" -}}
 {{ clrGreen }} {{- unstressed . -}} {{ clrPop }}
 {{- end -}}`
 	}
 
-	masterTpl, err := common.GetTpl()
-	exit(err)
+	var detail string
+	if o.sentences {
+		detail += `{{ template "sentences" . }}`
+	}
+	if o.grammar {
+		detail += `{{ template "grammar" . }}`
+	}
+	if detail != "" {
+		custom += `{{- define "wordDetail" }}` + detail + `{{ end -}}`
+	}
 
-	tpl, err := masterTpl.Parse(custom)
-	exit(err)
+	get := common.GetTpl
+	if !o.colors {
+		get = common.GetPlainTpl
+	}
+	masterTpl, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	tpl, err := masterTpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if o.noStress {
+		tpl.Funcs(common.NoStressFuncs())
+	}
+
+	return tpl.Parse(custom)
+}
+
+func checkLanguage(d *dict.Dict, lang string) error {
+	if !d.HasLanguage(lang) {
+		return fmt.Errorf("unknown language '%s', available: %s", lang, strings.Join(d.Languages(), ", "))
+	}
+	return nil
+}
+
+func getDict(lang string) (*dict.Dict, error) {
+	d, err := common.GetDict()
+	if err != nil {
+		return nil, err
+	}
+	return d, checkLanguage(d, lang)
+}
+
+// getDetailDict is getDict with declensions and conjugations, which noweb
+// builds read from the database file.
+func getDetailDict(lang string) (*dict.Dict, error) {
+	path, err := conf.DatabaseFile()
+	if err != nil {
+		return nil, err
+	}
+	d, err := common.GetDetailDict(path)
+	if err != nil {
+		return nil, err
+	}
+	return d, checkLanguage(d, lang)
+}
 
-	results, _ := d.Search(query, all, int(maxResults))
+// dict returns the dictionary, including declensions and conjugations if
+// they are to be printed.
+func (o options) dict() (*dict.Dict, error) {
+	if o.grammar {
+		return getDetailDict(o.lang)
+	}
+	return getDict(o.lang)
+}
+
+// lookup searches for exact matches and falls back to a fuzzy search.
+func lookup(d *dict.Dict, o options, query string) (results dict.Results, fuzzy bool) {
+	results, _ = d.SearchResults(o.lang, query, o.all, int(o.maxResults))
 	if len(results) == 0 {
-		results, _ = d.SearchFuzzy(query, all, int(maxResults))
+		fuzzy = true
+		results, _ = d.SearchFuzzyResults(o.lang, query, o.all, int(o.maxResults))
+	}
+	return
+}
+
+func search(w io.Writer, d *dict.Dict, tpl *template.Template, o options, query string) error {
+	results, fuzzy := lookup(d, o, query)
+	if o.history {
+		record(query, fuzzy, results.Words())
 	}
+	if o.output != "text" {
+		err := export.WriteLookups(w, o.output, []*export.Lookup{export.NewLookup(query, fuzzy, results)})
+		if err == nil && len(results) == 0 {
+			err = errNoResults
+		}
+		return err
+	}
+
 	if len(results) == 0 {
-		exit(errors.New("no results"))
+		return errNoResults
+	}
+	return o.writeWords(w, tpl, results.Words())
+}
+
+func cmdSearch(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	var o options
+	var interactive bool
+	var fullscreen bool
+	var batchMode bool
+	o.flags(fs)
+	fs.BoolVar(&interactive, "i", false, "interactive mode")
+	fs.BoolVar(&fullscreen, "tui", false, "full-screen mode with live results")
+	fs.BoolVar(&batchMode, "batch", false, "look up every line of the given files or stdin")
+	fs.Usage = func() {
+		mainUsage()
+		fmt.Fprintln(fs.Output(), "\nSearch flags:")
+		fs.PrintDefaults()
 	}
-	exit(tpl.Execute(os.Stdout, results))
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+
+	query := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if query == "" && !interactive && !fullscreen && !batchMode {
+		return errors.New("please provide a query")
+	}
+	if err := checkOutput(o.output); err != nil {
+		return err
+	}
+
+	d, err := o.dict()
+	if err != nil {
+		return err
+	}
+
+	if fullscreen {
+		return runTUI(d, o)
+	}
+
+	if batchMode {
+		return batch(os.Stdout, d, o, fs.Args())
+	}
+
+	if interactive {
+		return repl(d, o)
+	}
+
+	tpl, err := o.template()
+	if err != nil {
+		return err
+	}
+	return search(os.Stdout, d, tpl, o, query)
+}
+
+type command struct {
+	name  string
+	short string
+	run   func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"search", "look up words (default)", cmdSearch},
+		{"decline", "print the declension of a noun or adjective", cmdDecline},
+		{"conjugate", "print the conjugation of a verb", cmdConjugate},
+		{"info", "print everything known about a word by id", cmdInfo},
+		{"family", "print the words derived from the same root as a tree", cmdFamily},
+		{"root", "print the words with the same root and their morphemes", cmdRoot},
+		{"random", "print a random word", cmdRandom},
+		{"accent", "add stress marks to russian text from files or stdin", cmdAccent},
+		{"glossary", "list the words used in russian text from files or stdin", cmdGlossary},
+		{"save", "save words to a list", cmdSave},
+		{"list", "print the words in a list", cmdList},
+		{"lists", "print the names of all lists", cmdLists},
+		{"quiz", "review words with spaced repetition", cmdQuiz},
+		{"drill", "practice declensions and conjugations", cmdDrill},
+		{"history", "print statistics of recorded lookups", cmdHistory},
+		{"watch", "look up words as they are selected or written to a file", cmdWatch},
+	}
+}
+
+func usage(fs *flag.FlagSet, args string) func() {
+	return func() {
+		fmt.Fprintf(fs.Output(), "Usage: goru %s [flags] %s\n", fs.Name(), args)
+		fs.PrintDefaults()
+	}
+}
+
+func mainUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: goru [command] [flags] [args]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.short)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'goru <command> -h' for the flags of a command.")
+	fmt.Fprintln(os.Stderr, "Run 'goru -- <query>' or 'goru search <query>' to search for a command name.")
+	fmt.Fprintln(os.Stderr, "Run 'goru help themes' for the available color themes.")
+	if path, err := common.ConfigFile(); err == nil {
+		fmt.Fprintf(os.Stderr, "\nDefaults are read from %s (or $GORU_CONFIG), e.g.:\n", path)
+		fmt.Fprintln(os.Stderr, `
+  {"results": 5, "language": "de", "color": "auto", "theme": "256"}`)
+	}
+}
+
+func themesUsage() {
+	path, _ := themesFile()
+	if err := common.LoadThemes(path); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	fmt.Fprintf(os.Stderr, "Themes: %s\n", strings.Join(themeNames(), ", "))
+	fmt.Fprintf(os.Stderr, `
+Custom themes are read from %s, e.g.:
+
+  {"mine": {"green": "#98c379", "gray": "250", "stress": "bold underline bg:236 yellow"}}
+
+Keys: red, green, yellow, blue, magenta, cyan, gray and stress.
+Values: space separated attributes (bold, dim, italic, underline, reverse),
+color names, 256-color indexes (0-255) or #rrggbb, prefix with bg: for the
+background. Missing keys fall back to the default theme.
+`, path)
+}
+
+func main() {
+	var err error
+	conf, err = common.LoadConfig()
+	exit(err)
+
+	args := os.Args[1:]
+	if len(args) != 0 {
+		switch args[0] {
+		case "help":
+			if len(args) > 1 && args[1] == "themes" {
+				themesUsage()
+				return
+			}
+			mainUsage()
+			return
+		}
+		for _, c := range commands {
+			if c.name == args[0] {
+				exit(c.run(args[1:]))
+				return
+			}
+		}
+	}
+
+	exit(cmdSearch(args))
 }
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/history"
+	"github.com/frizinak/goru/openrussian"
+)
+
+func historyPath() (string, error) {
+	dir, err := common.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return history.File(dir), nil
+}
+
+var recorder *history.Recorder
+
+// record stores a lookup, failing to do so is not worth interrupting a
+// search for.
+func record(query string, fuzzy bool, words []*openrussian.Word) {
+	if recorder == nil {
+		path, err := historyPath()
+		if err != nil {
+			return
+		}
+		recorder = history.NewRecorder(path)
+	}
+	if err := recorder.Record(history.NewEntry(query, fuzzy, words)); err != nil {
+		fmt.Fprintf(os.Stderr, "could not record lookup: %s\n", err)
+	}
+}
+
+func translation(w *openrussian.Word) string {
+	tl := make([]string, len(w.Translations))
+	for i, t := range w.Translations {
+		tl[i] = t.Translation
+	}
+	return strings.Join(tl, "; ")
+}
+
+func cmdHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	var o options
+	var n, days int
+	var save string
+	var clear bool
+	fs.StringVar(&o.lang, "l", conf.Language, "translation language")
+	o.colorFlags(fs)
+	fs.IntVar(&n, "n", 10, "max amount of words per section")
+	fs.IntVar(&days, "days", 0, "only consider the last n days, 0 is everything")
+	fs.StringVar(&save, "save", "", "save the words you keep forgetting to this list")
+	fs.BoolVar(&clear, "clear", false, "delete the history")
+	fs.Usage = func() {
+		usage(fs, "")()
+		fmt.Fprintln(fs.Output(), `
+Lookups are only recorded with -history or "history": true in the config.`)
+	}
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	if clear {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	entries, err := history.Load(path)
+	if err != nil {
+		return err
+	}
+	if days > 0 {
+		entries = history.Since(entries, time.Now().AddDate(0, 0, -days))
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no lookups recorded, use -history or set \"history\": true in %s", configFileName())
+	}
+
+	d, err := getDict(o.lang)
+	if err != nil {
+		return err
+	}
+	stats := history.NewStats(entries, d.Words())
+
+	var head common.Style
+	if o.colors {
+		head = common.CurrentTheme().Get("blue")
+	}
+	f := o.former()
+	word := func(w *openrussian.Word) string { return f.form(w.Stressed) }
+	section := func(title string, t common.Table) error {
+		if len(t) == 0 {
+			return nil
+		}
+		if len(t) > n {
+			t = t[:n]
+		}
+		fmt.Printf("\n%s\n", head.Wrap(title))
+		return t.Write(os.Stdout, "  ", termWidth)
+	}
+
+	fmt.Printf("%d lookups, %d needed a correction\n", stats.Lookups, stats.Fuzzy)
+
+	t := make(common.Table, 0, len(stats.Words))
+	for _, c := range stats.Words {
+		w := c.Word.Localized(o.lang)
+		t = append(t, []string{strconv.Itoa(c.Count), word(w), translation(w)})
+	}
+	if err := section("most looked up", t); err != nil {
+		return err
+	}
+
+	t = make(common.Table, 0, len(stats.Typos))
+	for _, typo := range stats.Typos {
+		t = append(t, []string{strconv.Itoa(typo.Count), renderEdits(typo.Edits, o.colors), word(typo.Word)})
+	}
+	if err := section("recurring typos", t); err != nil {
+		return err
+	}
+
+	t = make(common.Table, 0, len(stats.Forgotten))
+	for _, c := range stats.Forgotten {
+		w := c.Word.Localized(o.lang)
+		t = append(t, []string{fmt.Sprintf("%d days", c.Count), word(w), translation(w)})
+	}
+	if err := section("words you keep forgetting", t); err != nil {
+		return err
+	}
+
+	if save == "" {
+		return nil
+	}
+	store, err := listStore()
+	if err != nil {
+		return err
+	}
+	list, err := store.Load(save)
+	if err != nil {
+		return err
+	}
+	added := 0
+	for _, c := range stats.Forgotten {
+		if list.Add(c.Word) {
+			added++
+		}
+	}
+	if err := store.Save(list); err != nil {
+		return err
+	}
+	fmt.Printf("\nsaved %d words to %s\n", added, save)
+	return nil
+}
+
+func configFileName() string {
+	path, err := common.ConfigFile()
+	if err != nil {
+		return "the config"
+	}
+	return path
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/export"
+	"github.com/frizinak/goru/openrussian"
+	"github.com/frizinak/goru/wordlist"
+)
+
+func listStore() (*wordlist.Store, error) {
+	dir, err := common.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return wordlist.NewStore(filepath.Join(dir, "lists")), nil
+}
+
+// resolve finds the word for an id, a stressed form or a query.
+// Homographs (e.g.: за́мок and замо́к) are returned as alternatives.
+func resolve(d *dict.Dict, o options, arg string) (*openrussian.Word, []*openrussian.Word, error) {
+	if id, err := strconv.ParseUint(arg, 10, 64); err == nil {
+		w, ok := d.Words()[openrussian.ID(id)]
+		if !ok {
+			return nil, nil, fmt.Errorf("no word with id %d", id)
+		}
+		return w, nil, nil
+	}
+
+	words := find(d, o, openrussian.Stressed(arg).Unstressed(), func(*openrussian.Word) bool { return true })
+	if len(words) == 0 {
+		return nil, nil, fmt.Errorf("no word found for '%s'", arg)
+	}
+
+	stressed := openrussian.Stressed(arg).String()
+	for i, w := range words {
+		if w.Stressed.String() == stressed {
+			return w, append(words[:i:i], words[i+1:]...), nil
+		}
+	}
+	return words[0], words[1:], nil
+}
+
+// listQueries returns the words in a file as written by hand: one per line,
+// anything after a tab is ignored as are lines starting with #.
+func listQueries(file string) ([]string, error) {
+	var q []string
+	err := readQueries([]string{file}, func(query string) {
+		if strings.HasPrefix(query, "#") {
+			return
+		}
+		if ix := strings.IndexByte(query, '\t'); ix != -1 {
+			query = strings.TrimSpace(query[:ix])
+		}
+		q = append(q, query)
+	})
+	return q, err
+}
+
+func cmdSave(args []string) error {
+	fs := flag.NewFlagSet("save", flag.ExitOnError)
+	var o options
+	var name, file string
+	var remove bool
+	fs.StringVar(&name, "list", wordlist.Default, "name of the list")
+	fs.StringVar(&file, "f", "", "also save the words in this file, one per line ('-' for stdin)")
+	fs.BoolVar(&remove, "rm", false, "remove the words from the list instead")
+	fs.Usage = usage(fs, "<word|id>...")
+	fs.Parse(args)
+	o.lang = conf.Language
+
+	queries := fs.Args()
+	if file != "" {
+		q, err := listQueries(file)
+		if err != nil {
+			return err
+		}
+		queries = append(queries, q...)
+	}
+	if len(queries) == 0 {
+		return errors.New("please provide a word")
+	}
+
+	store, err := listStore()
+	if err != nil {
+		return err
+	}
+	list, err := store.Load(name)
+	if err != nil {
+		return err
+	}
+
+	d, err := common.GetDict()
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, q := range queries {
+		w, alt, err := resolve(d, o, q)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed++
+			continue
+		}
+
+		switch {
+		case remove && list.Remove(w.ID):
+			fmt.Printf("removed %s (%d) from %s\n", w.Stressed, w.ID, name)
+		case remove:
+			fmt.Printf("%s (%d) is not in %s\n", w.Stressed, w.ID, name)
+		case list.Add(w):
+			fmt.Printf("saved %s (%d) to %s\n", w.Stressed, w.ID, name)
+		default:
+			fmt.Printf("%s (%d) is already in %s\n", w.Stressed, w.ID, name)
+		}
+
+		if len(alt) != 0 && !remove {
+			s := make([]string, len(alt))
+			for i, w := range alt {
+				s[i] = fmt.Sprintf("%s (%d)", w.Stressed, w.ID)
+			}
+			fmt.Printf("  also: %s, use the id or stressed form to pick one\n", strings.Join(s, ", "))
+		}
+	}
+
+	if err := store.Save(list); err != nil {
+		return err
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d words not found", failed, len(queries))
+	}
+	return nil
+}
+
+func cmdList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	var o options
+	o.flags(fs)
+	fs.Usage = usage(fs, "[name]")
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+	if err := checkOutput(o.output); err != nil {
+		return err
+	}
+
+	name := wordlist.Default
+	if fs.NArg() > 1 {
+		return errors.New("please provide a single list name")
+	}
+	if fs.NArg() == 1 {
+		name = fs.Arg(0)
+	}
+
+	store, err := listStore()
+	if err != nil {
+		return err
+	}
+	if !store.Exists(name) {
+		return fmt.Errorf("no list named '%s', see 'goru lists'", name)
+	}
+	list, err := store.Load(name)
+	if err != nil {
+		return err
+	}
+
+	d, err := o.dict()
+	if err != nil {
+		return err
+	}
+
+	words, missing := list.Words(d.Words())
+	for _, e := range missing {
+		fmt.Fprintf(os.Stderr, "%s (%d) is no longer in the dictionary\n", e.Word, e.ID)
+	}
+	for i := range words {
+		words[i] = words[i].Localized(o.lang)
+	}
+
+	if o.output != "text" {
+		lookup := &export.Lookup{Query: name, Results: make([]*export.Result, len(words))}
+		for i, w := range words {
+			lookup.Results[i] = export.NewResult(w, 0)
+		}
+		return export.WriteLookups(os.Stdout, o.output, []*export.Lookup{lookup})
+	}
+
+	tpl, err := o.template()
+	if err != nil {
+		return err
+	}
+	return o.writeWords(os.Stdout, tpl, words)
+}
+
+func cmdLists(args []string) error {
+	fs := flag.NewFlagSet("lists", flag.ExitOnError)
+	var remove string
+	fs.StringVar(&remove, "rm", "", "delete the list with this name")
+	fs.Usage = usage(fs, "")
+	fs.Parse(args)
+
+	store, err := listStore()
+	if err != nil {
+		return err
+	}
+	if remove != "" {
+		return store.Delete(remove)
+	}
+
+	names, err := store.Names()
+	if err != nil {
+		return err
+	}
+	t := make(common.Table, 0, len(names))
+	for _, name := range names {
+		list, err := store.Load(name)
+		if err != nil {
+			return err
+		}
+		t = append(t, []string{name, strconv.Itoa(len(list.Entries))})
+	}
+	return t.Write(os.Stdout, "", 0)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/export"
+	"github.com/frizinak/goru/openrussian"
+	"github.com/frizinak/goru/quiz"
+	"github.com/frizinak/goru/wordlist"
+)
+
+func quizFile() (string, error) {
+	dir, err := common.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "quiz.json"), nil
+}
+
+// quizWords returns the words of a list or the words matching filter sorted
+// by rank.
+func quizWords(d *dict.Dict, lang, list string, filter dict.Filter) ([]*openrussian.Word, error) {
+	var words []*openrussian.Word
+	if list != "" {
+		store, err := listStore()
+		if err != nil {
+			return nil, err
+		}
+		if !store.Exists(list) {
+			return nil, fmt.Errorf("no list named '%s', see 'goru lists'", list)
+		}
+		l, err := store.Load(list)
+		if err != nil {
+			return nil, err
+		}
+		words, _ = l.Words(filter.Filter(d.Words()))
+	} else {
+		words = export.SortedWords(filter.Filter(d.Words()))
+	}
+
+	n := make([]*openrussian.Word, 0, len(words))
+	for _, w := range words {
+		if len(w.TranslationsFor(lang)) != 0 {
+			n = append(n, w.Localized(lang))
+		}
+	}
+	return n, nil
+}
+
+func quizPrompt(w *openrussian.Word, dir quiz.Direction) string {
+	if dir == quiz.FromRussian {
+		return w.Stressed.String()
+	}
+	tl := make([]string, len(w.Translations))
+	for i, t := range w.Translations {
+		tl[i] = t.Translation
+	}
+	hint := w.WordType.String()
+	if w.NounInfo != nil {
+		hint = w.NounInfo.Gender.String() + " " + hint
+	}
+	if w.VerbInfo != nil && w.VerbInfo.Aspect != 0 {
+		hint = w.VerbInfo.Aspect.String() + " " + hint
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(tl, "; "), hint)
+}
+
+func days(d time.Duration) string {
+	n := int(d.Round(time.Hour).Hours()+23) / 24
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+func cmdQuiz(args []string) error {
+	fs := flag.NewFlagSet("quiz", flag.ExitOnError)
+	var o options
+	var list, levels, types, dirs string
+	var max, maxNew int
+	fs.StringVar(&o.lang, "l", conf.Language, "translation language")
+	o.colorFlags(fs)
+	fs.StringVar(&list, "list", "", "quiz the words in this list (see 'goru lists')")
+	fs.StringVar(&levels, "level", "", "comma separated levels (e.g.: A1,A2)")
+	fs.StringVar(&types, "type", "", "comma separated word types (e.g.: noun,verb)")
+	fs.StringVar(&dirs, "dir", "mixed", "ru: translate from russian, to-ru: translate to russian, mixed: both")
+	fs.IntVar(&max, "n", 20, "max amount of cards per session, 0 is unlimited")
+	fs.IntVar(&maxNew, "new", 10, "max amount of new words per session")
+	fs.Usage = func() {
+		usage(fs, "")()
+		fmt.Fprintln(fs.Output(), `
+Answer with the translation or the russian word, stress marks are optional.
+Small typos are accepted. An empty answer means you don't know,
+:q stops the session. Words you know are asked again after growing intervals.`)
+	}
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+
+	var filter dict.Filter
+	var err error
+	if filter.Levels, err = dict.ParseLevels(levels); err != nil {
+		return err
+	}
+	if filter.Types, err = dict.ParseTypes(types); err != nil {
+		return err
+	}
+	directions, err := quiz.ParseDirections(dirs)
+	if err != nil {
+		return err
+	}
+	if list != "" {
+		if err := wordlist.ValidName(list); err != nil {
+			return err
+		}
+	}
+
+	d, err := getDict(o.lang)
+	if err != nil {
+		return err
+	}
+	words, err := quizWords(d, o.lang, list, filter)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return errors.New("no words to quiz")
+	}
+
+	path, err := quizFile()
+	if err != nil {
+		return err
+	}
+	state, err := quiz.LoadState(path)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]openrussian.ID, len(words))
+	byID := make(map[openrussian.ID]*openrussian.Word, len(words))
+	for i, w := range words {
+		ids[i] = w.ID
+		byID[w.ID] = w
+	}
+
+	cards := state.Session(ids, directions, time.Now(), max, maxNew)
+	if len(cards) == 0 {
+		if next := state.NextDue(ids, time.Now()); !next.IsZero() {
+			fmt.Printf("nothing to review, next review in %s\n", days(time.Until(next)))
+			return nil
+		}
+		return errors.New("no words to quiz")
+	}
+
+	var green, red, gray common.Style
+	if o.colors {
+		theme := common.CurrentTheme()
+		green, red, gray = theme.Get("green"), theme.Get("red"), theme.Get("gray")
+	}
+	f := o.former()
+
+	in := bufio.NewScanner(os.Stdin)
+	var reviewed, correct int
+	for i, c := range cards {
+		w := byID[c.ID]
+		fmt.Printf("\n[%d/%d] %s\n> ", i+1, len(cards), quizPrompt(w, c.Direction))
+		if !in.Scan() {
+			fmt.Println()
+			break
+		}
+		answer := strings.TrimSpace(in.Text())
+		if answer == ":q" {
+			break
+		}
+
+		g := quiz.GradeAnswer(answer, quiz.Answers(w, c.Direction))
+		switch {
+		case g.Quality == 5:
+			fmt.Println(green.Wrap("correct"))
+		case g.Correct():
+			fmt.Printf("%s %s\n", green.Wrap("almost:"), renderEdits(g.Edits, o.colors))
+		case answer == "":
+			fmt.Println(red.Wrap("skipped"))
+		case g.Edits != nil:
+			fmt.Printf("%s %s\n", red.Wrap("wrong:"), renderEdits(g.Edits, o.colors))
+		default:
+			fmt.Println(red.Wrap("wrong"))
+		}
+
+		tl := make([]string, len(w.Translations))
+		for i, t := range w.Translations {
+			tl[i] = t.Translation
+		}
+		fmt.Printf("  %s  %s\n", f.form(w.Stressed), gray.Wrap(strings.Join(tl, "; ")))
+
+		state.Review(c, g.Quality, time.Now())
+		if err := state.Save(path); err != nil {
+			return err
+		}
+		reviewed++
+		if g.Correct() {
+			correct++
+		}
+	}
+
+	fmt.Printf("\n%d/%d correct", correct, reviewed)
+	if next := state.NextDue(ids, time.Now()); !next.IsZero() {
+		fmt.Printf(", next review in %s", days(time.Until(next)))
+	}
+	fmt.Println()
+	return nil
+}
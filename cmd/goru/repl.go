@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/dict"
+	"github.com/peterh/liner"
+)
+
+const replHelp = `type a word to look it up or one of the following commands:
+  :n <amount>  max amount of results
+  :all         toggle words without translation
+  :ns          toggle stress marks
+  :s           toggle example sentences
+  :lang <lang> translation language
+  :o <format>  output format
+  :q           quit
+`
+
+func historyFile() (string, error) {
+	dir, err := common.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history"), nil
+}
+
+func readHistory(l *liner.State, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	_, err = l.ReadHistory(f)
+	return err
+}
+
+func writeHistory(l *liner.State, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := l.WriteHistory(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// replCommand applies a :command to o, returning true if the repl should exit.
+func replCommand(w io.Writer, d *dict.Dict, o *options, cmd string) (bool, error) {
+	args := strings.Fields(cmd)
+	arg := func() (string, error) {
+		if len(args) != 2 {
+			return "", fmt.Errorf("usage: %s <value>", args[0])
+		}
+		return args[1], nil
+	}
+
+	switch args[0] {
+	case ":q", ":quit":
+		return true, nil
+	case ":h", ":help":
+		_, err := io.WriteString(w, replHelp)
+		return false, err
+	case ":n":
+		v, err := arg()
+		if err != nil {
+			return false, err
+		}
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || n == 0 {
+			return false, fmt.Errorf("invalid amount '%s'", v)
+		}
+		o.maxResults = uint(n)
+	case ":all", ":a":
+		o.all = !o.all
+	case ":ns":
+		o.noStress = !o.noStress
+	case ":s":
+		o.sentences = !o.sentences
+	case ":lang", ":l":
+		v, err := arg()
+		if err != nil {
+			return false, err
+		}
+		if err := checkLanguage(d, v); err != nil {
+			return false, err
+		}
+		o.lang = v
+	case ":o":
+		v, err := arg()
+		if err != nil {
+			return false, err
+		}
+		if err := checkOutput(v); err != nil {
+			return false, err
+		}
+		o.output = v
+	default:
+		return false, fmt.Errorf("unknown command '%s', type :help for a list", args[0])
+	}
+
+	return false, nil
+}
+
+func repl(d *dict.Dict, o options) error {
+	tpl, err := o.template()
+	if err != nil {
+		return err
+	}
+
+	l := liner.NewLiner()
+	defer l.Close()
+	l.SetCtrlCAborts(true)
+
+	hist, err := historyFile()
+	if err != nil {
+		return err
+	}
+	if err := readHistory(l, hist); err != nil {
+		return err
+	}
+
+	// Build the fuzzy indexes in the background so the first typo
+	// doesn't pay for it.
+	go d.InitRussianFuzzIndex()
+	go d.InitTranslationFuzzIndex(o.lang)
+
+	for {
+		line, err := l.Prompt("goru> ")
+		if err == liner.ErrPromptAborted || err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		l.AppendHistory(line)
+
+		if strings.HasPrefix(line, ":") {
+			quit, err := replCommand(os.Stdout, d, &o, line)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			if quit {
+				break
+			}
+			go d.InitTranslationFuzzIndex(o.lang)
+			if tpl, err = o.template(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = search(os.Stdout, d, tpl, o, line)
+		if errors.Is(err, errNoResults) {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeHistory(l, hist)
+}
@@ -0,0 +1,5 @@
+package main
+
+import "os"
+
+func resized() <-chan os.Signal { return nil }
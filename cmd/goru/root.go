@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/morph"
+	"github.com/frizinak/goru/openrussian"
+)
+
+// segmentation renders the morphemes of a word separated by dashes.
+func (f *former) segmentation(seg morph.Segmentation) string {
+	s := make([]string, len(seg))
+	for i, m := range seg {
+		s[i] = f.form(m.Stressed())
+	}
+	return strings.Join(s, "-")
+}
+
+func cmdRoot(args []string) error {
+	fs := flag.NewFlagSet("root", flag.ExitOnError)
+	var o options
+	fs.StringVar(&o.lang, "l", conf.Language, "translation language")
+	fs.BoolVar(&o.noStress, "ns", false, "don't print stress mark")
+	o.colorFlags(fs)
+	fs.Usage = usage(fs, "<root|word>")
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+	query, err := queryArgs(fs)
+	if err != nil {
+		return err
+	}
+
+	d, err := getDict(o.lang)
+	if err != nil {
+		return err
+	}
+
+	root := query
+	words := d.WithRoot(root)
+	if len(words) == 0 {
+		w, _, err := resolve(d, o, query)
+		if err != nil {
+			return fmt.Errorf("no words with root '%s'", query)
+		}
+		if root = d.Segment(w).Root(); root == "" {
+			return fmt.Errorf("%s can not be segmented", w.Word)
+		}
+		words = d.WithRoot(root)
+	}
+
+	f := o.former()
+	t := make(common.Table, 0, len(words))
+	for _, w := range words {
+		l := w.Localized(o.lang)
+		t = append(t, []string{
+			f.segmentation(d.Segment(w)),
+			l.WordType.String(),
+			translation(l),
+		})
+	}
+
+	fmt.Printf("root %s\n", strings.ToLower(openrussian.Stressed(root).Unstressed()))
+	return writeTable(os.Stdout, t)
+}
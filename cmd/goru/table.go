@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/openrussian"
+)
+
+// former renders stressed forms with a highlighted stressed vowel.
+type former struct {
+	noStress bool
+	stress   common.Style
+}
+
+func (f *former) form(s openrussian.Stressed) string {
+	if f.noStress {
+		return s.Unstressed()
+	}
+	if len(f.stress) == 0 {
+		return s.String()
+	}
+	p := s.Parse()
+	words := make([]string, len(p))
+	for i, w := range p {
+		if w.Stress == "" {
+			words[i] = w.Prefix
+			continue
+		}
+		words[i] = w.Prefix + f.stress.Wrap(w.Stress) + w.Suffix
+	}
+	return strings.Join(words, " ")
+}
+
+func (f *former) list(l openrussian.StressedList) string {
+	s := make([]string, len(l))
+	for i := range l {
+		s[i] = f.form(l[i])
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/frizinak/goru/common"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/openrussian"
+	"golang.org/x/term"
+)
+
+const tuiMaxResults = 100
+
+type keyType uint8
+
+const (
+	keyRune keyType = iota
+	keyEnter
+	keyBackspace
+	keyUp
+	keyDown
+	keyPgUp
+	keyPgDown
+	keyEsc
+	keyQuit
+	keyClear
+	keyDelWord
+)
+
+type key struct {
+	t keyType
+	r rune
+}
+
+// parseKeys converts raw terminal input to keys, a lone escape is treated as
+// the escape key.
+func parseKeys(b []byte) []key {
+	keys := make([]key, 0, len(b))
+	for len(b) != 0 {
+		if b[0] == 0x1b {
+			if len(b) == 1 {
+				keys = append(keys, key{t: keyEsc})
+				break
+			}
+			if len(b) >= 3 && (b[1] == '[' || b[1] == 'O') {
+				n := 3
+				switch b[2] {
+				case 'A':
+					keys = append(keys, key{t: keyUp})
+				case 'B':
+					keys = append(keys, key{t: keyDown})
+				case '5', '6':
+					if len(b) >= 4 && b[3] == '~' {
+						n = 4
+						t := keyPgUp
+						if b[2] == '6' {
+							t = keyPgDown
+						}
+						keys = append(keys, key{t: t})
+					}
+				}
+				b = b[n:]
+				continue
+			}
+			keys = append(keys, key{t: keyEsc})
+			b = b[1:]
+			continue
+		}
+
+		r, n := utf8.DecodeRune(b)
+		b = b[n:]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, key{t: keyEnter})
+		case 0x7f, 0x08:
+			keys = append(keys, key{t: keyBackspace})
+		case 0x03, 0x04:
+			keys = append(keys, key{t: keyQuit})
+		case 0x10:
+			keys = append(keys, key{t: keyUp})
+		case 0x0e:
+			keys = append(keys, key{t: keyDown})
+		case 0x15:
+			keys = append(keys, key{t: keyClear})
+		case 0x17:
+			keys = append(keys, key{t: keyDelWord})
+		default:
+			if unicode.IsPrint(r) {
+				keys = append(keys, key{t: keyRune, r: r})
+			}
+		}
+	}
+
+	return keys
+}
+
+type searchResult struct {
+	gen   int
+	words []*openrussian.Word
+	fuzzy []bool
+	edits dict.Edits
+}
+
+type tui struct {
+	d        *dict.Dict
+	o        options
+	tpl      *template.Template
+	out      *bufio.Writer
+	buf      *bytes.Buffer
+	w, h     int
+	query    []rune
+	gen      int
+	results  chan searchResult
+	words    []*openrussian.Word
+	fuzzy    []bool
+	edits    dict.Edits
+	sel      int
+	offset   int
+	detail   []string
+	scroll   int
+	inDetail bool
+
+	// info styles the result count and marker the selection, both are
+	// empty without colors.
+	info, marker common.Style
+}
+
+func (t *tui) search() {
+	t.gen++
+	gen, q := t.gen, string(t.query)
+	if strings.TrimSpace(q) == "" {
+		t.words, t.fuzzy, t.edits, t.sel, t.offset = nil, nil, nil, 0, 0
+		return
+	}
+
+	go func() {
+		results, cyr := t.d.SearchFuzzyResults(t.o.lang, q, t.o.all, tuiMaxResults)
+		words := results.Words()
+		fuzzy := make([]bool, len(results))
+		for i, r := range results {
+			fuzzy[i] = r.Distance() != 0
+		}
+		var edits dict.Edits
+		if cyr && len(words) != 0 {
+			edits = dict.LevenshteinEdits([]rune(words[0].Word), []rune(q))
+			if !edits.HasEdits() {
+				edits = nil
+			}
+		}
+		t.results <- searchResult{gen, words, fuzzy, edits}
+	}()
+}
+
+// renderEdits highlights the typos in edits, without colors they are
+// surrounded by brackets.
+func renderEdits(edits dict.Edits, colors bool) string {
+	add, change := common.Style(nil), common.Style(nil)
+	if colors {
+		add, change = common.Style{41, 31}, common.Style{41, 97}
+	}
+	s := make([]string, 0, len(edits))
+	for _, e := range edits {
+		str := e.String()
+		if e.Type != dict.EditNone && !colors {
+			str = "[" + str + "]"
+		}
+		switch e.Type {
+		case dict.EditNone:
+			s = append(s, str)
+		case dict.EditAdd:
+			s = append(s, add.Wrap(str))
+		default:
+			s = append(s, change.Wrap(str))
+		}
+	}
+	return strings.Join(s, "")
+}
+
+func (t *tui) renderWord(w *openrussian.Word) string {
+	t.buf.Reset()
+	if err := t.tpl.ExecuteTemplate(t.buf, "wordStr", w); err != nil {
+		return err.Error()
+	}
+	s := t.buf.String()
+	if w.NounInfo != nil {
+		s += " " + w.NounInfo.Gender.String()
+	}
+	s += " " + w.WordType.String()
+
+	tl := make([]string, len(w.Translations))
+	for i, tr := range w.Translations {
+		tl[i] = tr.Translation
+	}
+	return s + "  " + strings.Join(tl, "; ")
+}
+
+// expand renders the selected word in the detail view.
+func (t *tui) expand() {
+	t.buf.Reset()
+	if err := t.tpl.ExecuteTemplate(t.buf, "word", t.words[t.sel]); err != nil {
+		t.detail = []string{err.Error()}
+		return
+	}
+	t.detail = t.detail[:0]
+	for _, l := range strings.Split(strings.TrimRight(t.buf.String(), "\n"), "\n") {
+		t.detail = append(t.detail, strings.Split(common.Wrap(l, t.w, "  "), "\n")...)
+	}
+	t.scroll = 0
+	t.inDetail = true
+}
+
+func (t *tui) rows() int { return t.h - 2 }
+
+func (t *tui) move(n int) {
+	if t.inDetail {
+		t.scroll += n
+		if max := len(t.detail) - t.rows(); t.scroll > max {
+			t.scroll = max
+		}
+		if t.scroll < 0 {
+			t.scroll = 0
+		}
+		return
+	}
+
+	t.sel += n
+	if t.sel >= len(t.words) {
+		t.sel = len(t.words) - 1
+	}
+	if t.sel < 0 {
+		t.sel = 0
+	}
+	if t.sel < t.offset {
+		t.offset = t.sel
+	}
+	if t.sel >= t.offset+t.rows() {
+		t.offset = t.sel - t.rows() + 1
+	}
+}
+
+func (t *tui) size() {
+	if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 && h > 0 {
+		t.w, t.h = w, h
+	}
+}
+
+func (t *tui) render() error {
+	t.size()
+
+	lines := make([]string, 0, t.h)
+	prompt := "> " + string(t.query)
+	lines = append(lines, prompt)
+
+	info := t.info.Wrap(fmt.Sprintf("%d results [%s]", len(t.words), t.o.lang))
+	if t.edits != nil {
+		info = renderEdits(t.edits, t.o.colors) + "  " + info
+	}
+	lines = append(lines, info)
+
+	if t.inDetail {
+		for i := t.scroll; i < len(t.detail) && len(lines) < t.h; i++ {
+			lines = append(lines, t.detail[i])
+		}
+	} else {
+		for i := t.offset; i < len(t.words) && len(lines) < t.h; i++ {
+			l := "  " + t.renderWord(t.words[i])
+			if i == t.sel {
+				l = t.marker.Wrap(">") + " " + l[2:]
+			}
+			lines = append(lines, l)
+		}
+	}
+
+	t.out.WriteString("\033[H")
+	for i, l := range lines {
+		if i != 0 {
+			t.out.WriteString("\r\n")
+		}
+		t.out.WriteString(common.Truncate(l, t.w))
+		t.out.WriteString("\033[K")
+	}
+	t.out.WriteString("\033[J")
+	fmt.Fprintf(t.out, "\033[1;%dH", common.Width(prompt)+1)
+
+	return t.out.Flush()
+}
+
+// handle processes a key, returning false if the tui should exit.
+func (t *tui) handle(k key) bool {
+	switch k.t {
+	case keyQuit:
+		return false
+	case keyEsc:
+		if !t.inDetail {
+			return false
+		}
+		t.inDetail = false
+	case keyEnter:
+		if t.inDetail {
+			t.inDetail = false
+			break
+		}
+		if len(t.words) != 0 {
+			if t.o.history {
+				q := strings.TrimSpace(string(t.query))
+				record(q, t.fuzzy[t.sel], []*openrussian.Word{t.words[t.sel]})
+			}
+			t.expand()
+		}
+	case keyUp:
+		t.move(-1)
+	case keyDown:
+		t.move(1)
+	case keyPgUp:
+		t.move(-t.rows())
+	case keyPgDown:
+		t.move(t.rows())
+	case keyRune, keyBackspace, keyClear, keyDelWord:
+		switch k.t {
+		case keyRune:
+			t.query = append(t.query, k.r)
+		case keyBackspace:
+			if len(t.query) != 0 {
+				t.query = t.query[:len(t.query)-1]
+			}
+		case keyClear:
+			t.query = t.query[:0]
+		case keyDelWord:
+			q := strings.TrimRight(string(t.query), " ")
+			t.query = []rune(q[:strings.LastIndex(q, " ")+1])
+		}
+		t.inDetail = false
+		t.search()
+	}
+
+	return true
+}
+
+func runTUI(d *dict.Dict, o options) error {
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return fmt.Errorf("tui mode requires a terminal")
+	}
+
+	o.sentences = true
+	tpl, err := o.template()
+	if err != nil {
+		return err
+	}
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(in, state)
+
+	stdout := bufio.NewWriter(os.Stdout)
+	stdout.WriteString("\033[?1049h")
+	defer func() {
+		stdout.WriteString("\033[?1049l")
+		stdout.Flush()
+	}()
+
+	go d.InitRussianFuzzIndex()
+	go d.InitTranslationFuzzIndex(o.lang)
+
+	keys := make(chan []key)
+	go func() {
+		b := make([]byte, 256)
+		for {
+			n, err := os.Stdin.Read(b)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- parseKeys(b[:n])
+		}
+	}()
+
+	t := &tui{
+		d:       d,
+		o:       o,
+		tpl:     tpl,
+		out:     stdout,
+		buf:     bytes.NewBuffer(nil),
+		w:       80,
+		h:       24,
+		results: make(chan searchResult, 1),
+	}
+	if o.colors {
+		theme := common.CurrentTheme()
+		t.info, t.marker = theme.Get("gray"), append(common.Style{1}, theme.Get("blue")...)
+	}
+
+	resize := resized()
+	for {
+		if err := t.render(); err != nil {
+			return err
+		}
+
+		select {
+		case ks, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range ks {
+				if !t.handle(k) {
+					return nil
+				}
+			}
+		case r := <-t.results:
+			if r.gen != t.gen {
+				continue
+			}
+			t.words, t.fuzzy, t.edits, t.sel, t.offset = r.words, r.fuzzy, r.edits, 0, 0
+		case <-resize:
+			t.size()
+			if t.inDetail {
+				t.expand()
+			}
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/frizinak/goru/accent"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/openrussian"
+)
+
+// maxWatchWords is the max amount of words looked up in a single selection,
+// anything longer is probably not meant for us.
+const maxWatchWords = 8
+
+// selectionCommand returns the command that prints the clipboard or primary
+// selection on the current display server.
+func selectionCommand(primary bool) ([]string, error) {
+	type candidate struct {
+		env       string
+		clipboard []string
+		primary   []string
+	}
+	candidates := []candidate{
+		{"WAYLAND_DISPLAY", []string{"wl-paste", "-n"}, []string{"wl-paste", "-n", "-p"}},
+		{"DISPLAY", []string{"xclip", "-o", "-selection", "clipboard"}, []string{"xclip", "-o", "-selection", "primary"}},
+		{"DISPLAY", []string{"xsel", "-o", "-b"}, []string{"xsel", "-o", "-p"}},
+	}
+	for _, c := range candidates {
+		if os.Getenv(c.env) == "" {
+			continue
+		}
+		cmd := c.clipboard
+		if primary {
+			cmd = c.primary
+		}
+		if _, err := exec.LookPath(cmd[0]); err == nil {
+			return cmd, nil
+		}
+	}
+	return nil, errors.New("no wl-paste (wayland), xclip or xsel (x11) found, use -f to watch a file instead")
+}
+
+// send sends text on out, false if stop was closed first.
+func send(text string, out chan<- string, stop <-chan struct{}) bool {
+	select {
+	case out <- text:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// wait sleeps for interval, false if stop was closed first.
+func wait(interval time.Duration, stop <-chan struct{}) bool {
+	t := time.NewTimer(interval)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// pollCommand sends the output of cmd every interval, except for the first
+// as it was selected before we started.
+func pollCommand(cmd []string, interval time.Duration, out chan<- string, stop <-chan struct{}) error {
+	var prev string
+	for i := 0; ; i++ {
+		c := exec.Command(cmd[0], cmd[1:]...)
+		b, err := c.Output()
+		// An empty selection is an error for most tools.
+		if err == nil && string(b) != prev {
+			prev = string(b)
+			if i != 0 && !send(prev, out, stop) {
+				return nil
+			}
+		}
+		if !wait(interval, stop) {
+			return nil
+		}
+	}
+}
+
+// readFIFO sends every line written to the fifo at path. It is opened read
+// and write so opening does not wait for a writer and the fifo stays open
+// between writers, stop closes it.
+func readFIFO(path string, out chan<- string, stop <-chan struct{}) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+		case <-done:
+		}
+		f.Close()
+	}()
+
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		if !send(scan.Text(), out, stop) {
+			return nil
+		}
+	}
+	select {
+	case <-stop:
+		return nil
+	default:
+		return scan.Err()
+	}
+}
+
+// fileState is the modification time and size of a file.
+type fileState struct {
+	mod  time.Time
+	size int64
+}
+
+func (s fileState) equal(o fileState) bool {
+	return s.mod.Equal(o.mod) && s.size == o.size
+}
+
+// pollFile sends the contents of the file at path whenever it changes.
+// Files are usually truncated before being written, so a change is only
+// read once two polls agree on it and empty contents are skipped.
+func pollFile(path string, interval time.Duration, out chan<- string, stop <-chan struct{}) error {
+	sent := fileState{size: -1}
+	if fi, err := os.Stat(path); err == nil {
+		sent = fileState{fi.ModTime(), fi.Size()}
+	}
+	seen := sent
+	for {
+		fi, err := os.Stat(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil {
+			cur := fileState{fi.ModTime(), fi.Size()}
+			if cur.equal(seen) && !cur.equal(sent) {
+				sent = cur
+				b, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				if len(bytes.TrimSpace(b)) != 0 && !send(string(b), out, stop) {
+					return nil
+				}
+			}
+			seen = cur
+		}
+		if !wait(interval, stop) {
+			return nil
+		}
+	}
+}
+
+// watchSource sends the text selected in file, a fifo or the selection of
+// the display server until stop is closed.
+func watchSource(file string, primary bool, interval time.Duration, out chan<- string, stop <-chan struct{}) error {
+	if file == "" {
+		cmd, err := selectionCommand(primary)
+		if err != nil {
+			return err
+		}
+		return pollCommand(cmd, interval, out, stop)
+	}
+
+	fi, err := os.Stat(file)
+	if err == nil && fi.Mode()&os.ModeNamedPipe != 0 {
+		return readFIFO(file, out, stop)
+	}
+	return pollFile(file, interval, out, stop)
+}
+
+// selection extracts the russian words from text, nil if there are none or
+// too many.
+func selection(text string) []string {
+	var words []string
+	for _, t := range accent.Tokenize(text) {
+		if !accent.IsWord(t) {
+			continue
+		}
+		words = append(words, t)
+		if len(words) > maxWatchWords {
+			return nil
+		}
+	}
+	return words
+}
+
+// lemmas looks up the words an (inflected) form belongs to, falling back to
+// a regular search.
+func lemmas(d *dict.Dict, o options, form string) []*openrussian.Word {
+	words := make([]*openrussian.Word, 0, 1)
+	for _, w := range d.Lemmas(form) {
+		if o.all || len(w.TranslationsFor(o.lang)) != 0 {
+			words = append(words, w.Localized(o.lang))
+		}
+	}
+	if len(words) == 0 {
+		res, _ := lookup(d, o, form)
+		return res.Words()
+	}
+	if o.maxResults != 0 && len(words) > int(o.maxResults) {
+		words = words[:o.maxResults]
+	}
+	return words
+}
+
+// notify runs cmd with a title and body argument.
+func notify(cmd []string, title, body string) error {
+	args := append(append([]string{}, cmd[1:]...), title, body)
+	c := exec.Command(cmd[0], args...)
+	c.Stdout, c.Stderr = os.Stdout, os.Stderr
+	return c.Run()
+}
+
+func watchLookup(w io.Writer, d *dict.Dict, tpl *template.Template, o options, notifyCmd []string, text string) error {
+	for _, form := range selection(text) {
+		words := lemmas(d, o, form)
+		if o.history {
+			record(form, false, words)
+		}
+
+		if notifyCmd == nil {
+			fmt.Fprintf(w, "> %s\n", form)
+			if len(words) == 0 {
+				fmt.Fprintln(w, "no results")
+				continue
+			}
+			if err := o.writeWords(w, tpl, words); err != nil {
+				return err
+			}
+			continue
+		}
+
+		body := "no results"
+		if len(words) != 0 {
+			lines := make([]string, len(words))
+			for i, w := range words {
+				lines[i] = fmt.Sprintf("%s — %s", w.Stressed, translation(w))
+			}
+			body = strings.Join(lines, "\n")
+		}
+		if err := notify(notifyCmd, form, body); err != nil {
+			fmt.Fprintf(os.Stderr, "notify failed: %s\n", err)
+		}
+	}
+	return nil
+}
+
+func cmdWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	var o options
+	var file, notifyCmd string
+	var clipboard bool
+	var interval time.Duration
+	o.flags(fs)
+	fs.BoolVar(&clipboard, "clipboard", false, "watch the clipboard instead of the primary selection")
+	fs.StringVar(&file, "f", "", "watch this file or fifo instead of the selection")
+	fs.StringVar(&notifyCmd, "notify", "", "run this command with the word and translations as arguments instead of printing (e.g.: notify-send)")
+	fs.DurationVar(&interval, "interval", 300*time.Millisecond, "poll interval")
+	fs.Usage = usage(fs, "")
+	fs.Parse(args)
+	if err := o.setup(); err != nil {
+		return err
+	}
+	if o.output != "text" {
+		return errors.New("watch only supports -o text")
+	}
+
+	var ncmd []string
+	if notifyCmd != "" {
+		ncmd = strings.Fields(notifyCmd)
+		if _, err := exec.LookPath(ncmd[0]); err != nil {
+			return err
+		}
+	}
+
+	d, err := getDetailDict(o.lang)
+	if err != nil {
+		return err
+	}
+	tpl, err := o.template()
+	if err != nil {
+		return err
+	}
+	d.InitFormIndex()
+	go d.InitRussianFuzzIndex()
+
+	texts := make(chan string, 1)
+	errs := make(chan error, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() { errs <- watchSource(file, !clipboard, interval, texts, stop) }()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+
+	var last string
+	for {
+		select {
+		case <-sig:
+			return nil
+		case err := <-errs:
+			return err
+		case text := <-texts:
+			text = strings.TrimSpace(text)
+			if text == last || !utf8.ValidString(text) {
+				continue
+			}
+			last = text
+			buf := bytes.NewBuffer(nil)
+			if err := watchLookup(buf, d, tpl, o, ncmd, text); err != nil {
+				return err
+			}
+			if _, err := io.Copy(os.Stdout, buf); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatchFIFO(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fifo")
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		t.Skip(err)
+	}
+
+	texts := make(chan string)
+	errs := make(chan error, 1)
+	stop := make(chan struct{})
+	go func() { errs <- watchSource(path, false, time.Millisecond, texts, stop) }()
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("стол\nделать\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	for _, exp := range []string{"стол", "делать"} {
+		if got := receive(t, texts); got != exp {
+			t.Errorf("expected %q got %q", exp, got)
+		}
+	}
+	close(stop)
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("reader did not stop")
+	}
+}
+
+func TestWatchFIFOStop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fifo")
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		t.Skip(err)
+	}
+
+	errs := make(chan error, 1)
+	stop := make(chan struct{})
+	go func() { errs <- watchSource(path, false, time.Millisecond, make(chan string), stop) }()
+
+	// Without a writer the reader should still stop.
+	time.Sleep(20 * time.Millisecond)
+	close(stop)
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("reader did not stop")
+	}
+}
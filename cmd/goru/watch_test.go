@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelection(t *testing.T) {
+	words := func(n int) string {
+		return strings.TrimSpace(strings.Repeat("стол ", n))
+	}
+
+	tests := []struct {
+		text string
+		exp  []string
+	}{
+		{"", nil},
+		{"hello world", nil},
+		{"the стол, and делать!", []string{"стол", "делать"}},
+		{words(maxWatchWords), strings.Fields(words(maxWatchWords))},
+		{words(maxWatchWords + 1), nil},
+	}
+
+	for _, test := range tests {
+		got := selection(test.text)
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("selection(%q): expected %q got %q", test.text, test.exp, got)
+		}
+	}
+}
+
+func receive(t *testing.T, texts <-chan string) string {
+	t.Helper()
+	select {
+	case text := <-texts:
+		return text
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for text")
+	}
+	return ""
+}
+
+// replace atomically replaces the file at path with data.
+func replace(t *testing.T, path, data string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "selection")
+	replace(t, path, "before")
+
+	texts := make(chan string)
+	errs := make(chan error, 1)
+	stop := make(chan struct{})
+	go func() { errs <- watchSource(path, false, time.Millisecond, texts, stop) }()
+
+	select {
+	case text := <-texts:
+		t.Fatalf("contents from before watching were sent: %q", text)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	replace(t, path, "стол")
+	if got := receive(t, texts); got != "стол" {
+		t.Errorf("expected %q got %q", "стол", got)
+	}
+
+	// Truncating is not a selection.
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case text := <-texts:
+		t.Fatalf("empty file was sent: %q", text)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	replace(t, path, "делать")
+	if got := receive(t, texts); got != "делать" {
+		t.Errorf("expected %q got %q", "делать", got)
+	}
+
+	close(stop)
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
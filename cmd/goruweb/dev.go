@@ -1,4 +1,4 @@
-// +build !prod
+// +build !prod,!noweb
 
 package main
 
@@ -7,12 +7,15 @@ import (
 
 	_ "net/http/pprof"
 
+	"github.com/frizinak/goru/common"
 	"github.com/frizinak/gotls/tls"
 )
 
 const Prod = false
 
-func run(s *tls.Server, addr string) error {
-	go http.ListenAndServe(":6060", nil)
-	return s.Start(addr, false)
+func run(s *tls.Server, conf common.WebConfig) error {
+	if conf.Debug != "" {
+		go http.ListenAndServe(conf.Debug, nil)
+	}
+	return s.Start(conf.Listen, false)
 }
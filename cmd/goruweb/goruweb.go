@@ -1,3 +1,5 @@
+// +build !noweb
+
 package main
 
 import (
@@ -13,6 +15,7 @@ import (
 	"image/png"
 	"io"
 	"log"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
@@ -22,11 +25,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/frizinak/goru/accent"
 	"github.com/frizinak/goru/common"
 	"github.com/frizinak/goru/data"
 	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/history"
 	"github.com/frizinak/goru/image"
+	"github.com/frizinak/goru/morph"
 	"github.com/frizinak/goru/openrussian"
+	"github.com/frizinak/goru/quiz"
 	"github.com/frizinak/gotls/simplehttp"
 	"github.com/frizinak/gotls/tls"
 )
@@ -44,13 +51,21 @@ type Config struct {
 }
 
 type App struct {
+	log          *log.Logger
 	prod         bool
+	defaultLang  string
+	home         openrussian.ID
+	history      *history.Recorder
 	cpurate      chan struct{}
 	netrate      chan struct{}
 	conf         Config
 	homeTpl      *template.Template
 	wordsTpl     *template.Template
 	wordTpl      *template.Template
+	accentTpl    *template.Template
+	drillTpl     *template.Template
+	familyTpl    *template.Template
+	rootTpl      *template.Template
 	resultsTpl   *template.Template
 	scrapableTpl *template.Template
 
@@ -58,6 +73,11 @@ type App struct {
 		l     sync.Mutex
 		words []*openrussian.Word
 	}
+
+	drill struct {
+		l     sync.Mutex
+		words map[string][]*openrussian.Word
+	}
 }
 
 func (app *App) ratelimit(h simplehttp.HandleFunc) simplehttp.HandleFunc {
@@ -151,6 +171,12 @@ func (app *App) route(r *http.Request, l *log.Logger) (simplehttp.HandleFunc, in
 	case len(u.parts) == 3 && u.parts[0] == "w" && u.parts[1] == "i":
 		return app.wrapArgs(app.handleWordInfo, u.parts), 0
 
+	case len(u.parts) == 3 && u.parts[0] == "w" && u.parts[1] == "f":
+		return app.wrapArgs(app.handleWordFamily, u.parts), 0
+
+	case len(u.parts) == 2 && u.parts[0] == "r":
+		return app.wrapArgs(app.handleRoot, u.parts), 0
+
 	case len(u.parts) == 3 && u.parts[0] == "a":
 		return app.wrapArgs(app.handleAudio, u.parts), 0
 
@@ -165,6 +191,15 @@ func (app *App) route(r *http.Request, l *log.Logger) (simplehttp.HandleFunc, in
 
 	case len(u.parts) == 2 && u.parts[0] == "f":
 		return app.wrapArgs(app.handleAsset, u.parts), 0
+
+	case len(u.parts) == 2 && u.parts[0] == "l":
+		return app.wrapArgs(app.handleLang, u.parts), 0
+
+	case len(u.parts) == 1 && u.parts[0] == "accent":
+		return app.ratelimit(app.wrapArgs(app.handleAccent, u.parts)), 0
+
+	case len(u.parts) == 1 && u.parts[0] == "drill":
+		return app.ratelimit(app.wrapArgs(app.handleDrill, u.parts)), 0
 	}
 
 	return nil, 0
@@ -175,7 +210,9 @@ var b64e = base64.NewEncoding(Base64Chars).WithPadding(base64.NoPadding)
 func esc(i string) string                    { return url.PathEscape(i) }
 func absWord(w *openrussian.Word) string     { return fmt.Sprintf("/w/%s", esc(w.Word)) }
 func absWordInfo(w *openrussian.Word) string { return fmt.Sprintf("/w/i/%d", w.ID) }
+func absFamily(w *openrussian.Word) string   { return fmt.Sprintf("/w/f/%d", w.ID) }
 func absImg(w *openrussian.Word) string      { return fmt.Sprintf("/i/%d.png", w.ID) }
+func absRoot(root string) string             { return fmt.Sprintf("/r/%s", esc(root)) }
 func absAudio(w *openrussian.Word) string    { return fmt.Sprintf("/a/%d/%s", w.ID, esc(w.Word)) }
 
 func fnhash(data string) string {
@@ -321,13 +358,100 @@ func (app *App) initScrapable() error {
 	return nil
 }
 
+// drillWords returns the translated words that can be drilled with o at the
+// given levels, cached per kind, levels and language.
+func (app *App) drillWords(dct *dict.Dict, o quiz.DrillOptions, filter dict.Filter, lang string) []*openrussian.Word {
+	levels := make([]bool, openrussian.C2+1)
+	for _, l := range filter.Levels {
+		levels[l] = true
+	}
+	key := fmt.Sprintf("%t %t %v %s", o.Declension, o.Conjugation, levels, lang)
+
+	app.drill.l.Lock()
+	defer app.drill.l.Unlock()
+	if words, ok := app.drill.words[key]; ok {
+		return words
+	}
+	if app.drill.words == nil {
+		app.drill.words = make(map[string][]*openrussian.Word)
+	}
+
+	words := make([]*openrussian.Word, 0)
+	for _, w := range filter.Filter(dct.Words()) {
+		if o.Drillable(w) && len(w.TranslationsFor(lang)) != 0 {
+			words = append(words, w)
+		}
+	}
+	app.drill.words[key] = words
+	return words
+}
+
+const langCookie = "lang"
+
+func (app *App) lang(r *http.Request) (string, error) {
+	dict, err := common.GetDict()
+	if err != nil {
+		return "", err
+	}
+	c, err := r.Cookie(langCookie)
+	if err != nil || !dict.HasLanguage(c.Value) {
+		return app.defaultLang, nil
+	}
+	return c.Value, nil
+}
+
+func (app *App) page(r *http.Request, d WordPage) (WordPage, error) {
+	dict, err := common.GetDict()
+	if err != nil {
+		return d, err
+	}
+	d.Lang, err = app.lang(r)
+	d.Languages = dict.Languages()
+	return d, err
+}
+
+func (app *App) handleLang(w http.ResponseWriter, r *http.Request, p []string) (int, error) {
+	dict, err := common.GetDict()
+	if err != nil {
+		return 0, err
+	}
+	if !dict.HasLanguage(p[1]) {
+		return http.StatusNotFound, nil
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     langCookie,
+		Value:    p[1],
+		Path:     "/",
+		MaxAge:   86400 * 365,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	loc := "/"
+	if ref, err := url.Parse(r.Referer()); err == nil && ref.Host == r.Host && ref.Path != "" {
+		loc = ref.Path
+	}
+	w.Header().Set("Location", loc)
+	return http.StatusSeeOther, nil
+}
+
 func (app *App) handleHome(w http.ResponseWriter, r *http.Request, p []string) (int, error) {
 	dict, err := common.GetDict()
 	if err != nil {
 		return 0, err
 	}
-	words := dict.Words()
-	d := WordPage{Query: "", Words: []*openrussian.Word{words[33002]}}
+	lang, err := app.lang(r)
+	if err != nil {
+		return 0, err
+	}
+	words := make([]*openrussian.Word, 0, 1)
+	if word := dict.Words()[app.home]; word != nil {
+		words = append(words, word.Localized(lang))
+	}
+	d, err := app.page(r, WordPage{Query: "", Words: words})
+	if err != nil {
+		return 0, err
+	}
 
 	w.Header().Set("content-type", "text/html")
 	return 0, app.wordsTpl.Execute(w, d)
@@ -482,9 +606,14 @@ func (app *App) handleWord(w http.ResponseWriter, r *http.Request, p []string) (
 		return 0, err
 	}
 
+	lang, err := app.lang(r)
+	if err != nil {
+		return 0, err
+	}
+
 	const max = 30
 	var res []*openrussian.Word
-	res, cyr := dct.SearchFuzzy(p[1], true, max)
+	res, cyr := dct.SearchFuzzy(lang, p[1], true, max)
 
 	var audio string
 	if len(res) != 0 && strings.EqualFold(p[1], res[0].Word) {
@@ -503,7 +632,17 @@ func (app *App) handleWord(w http.ResponseWriter, r *http.Request, p []string) (
 		}
 	}
 
-	d := WordPage{Query: p[1], Edits: edits, Audio: audio, Words: res}
+	if app.history != nil && !xhr {
+		// Failing to record is not worth failing the search for.
+		if err := app.history.Record(history.NewEntry(p[1], edits != nil, res)); err != nil {
+			app.log.Printf("could not record lookup: %s", err)
+		}
+	}
+
+	d, err := app.page(r, WordPage{Query: p[1], Edits: edits, Audio: audio, Words: res})
+	if err != nil {
+		return 0, err
+	}
 	w.Header().Set("content-type", "text/html")
 	if xhr {
 		return 0, app.resultsTpl.Execute(w, d)
@@ -527,31 +666,246 @@ func (app *App) handleWordInfo(w http.ResponseWriter, r *http.Request, p []strin
 		return http.StatusNotFound, nil
 	}
 
-	d := WordPage{Query: word.Word, Edits: nil, Audio: "", Words: []*openrussian.Word{word}}
+	lang, err := app.lang(r)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := app.page(r, WordPage{Query: word.Word, Edits: nil, Audio: "", Words: []*openrussian.Word{word.Localized(lang)}})
+	if err != nil {
+		return 0, err
+	}
 	w.Header().Set("content-type", "text/html")
 	return 0, app.wordTpl.Execute(w, d)
 }
 
-type WordPage struct {
-	Query string
-	Edits dict.Edits
-	Audio string
-	Next  string
+// familyNodes converts a dict.Family to FamilyNodes with localized words.
+func familyNodes(f *dict.Family, lang string, current openrussian.ID) *FamilyNode {
+	n := &FamilyNode{Word: f.Word.Localized(lang), Current: f.Word.ID == current}
+	for _, d := range f.Derived {
+		n.Derived = append(n.Derived, familyNodes(d, lang, current))
+	}
+	return n
+}
+
+func (app *App) handleWordFamily(w http.ResponseWriter, r *http.Request, p []string) (int, error) {
+	id, err := strconv.Atoi(p[2])
+	if err != nil {
+		return http.StatusNotFound, nil
+	}
+
+	dct, err := common.GetDict()
+	if err != nil {
+		return 0, err
+	}
+	word := dct.Words()[openrussian.ID(id)]
+	if word == nil {
+		return http.StatusNotFound, nil
+	}
+
+	lang, err := app.lang(r)
+	if err != nil {
+		return 0, err
+	}
+
+	w.Header().Set("content-type", "text/html")
+	return 0, app.familyTpl.Execute(w, FamilyPage{
+		Word:   word,
+		Family: familyNodes(dct.WordFamily(word), lang, word.ID),
+	})
+}
+
+func (app *App) handleRoot(w http.ResponseWriter, r *http.Request, p []string) (int, error) {
+	dct, err := common.GetDict()
+	if err != nil {
+		return 0, err
+	}
+
+	words := dct.WithRoot(p[1])
+	if len(words) == 0 {
+		return http.StatusNotFound, nil
+	}
+
+	lang, err := app.lang(r)
+	if err != nil {
+		return 0, err
+	}
+
+	d := RootPage{Root: strings.ToLower(p[1]), Words: make([]*openrussian.Word, len(words))}
+	for i, word := range words {
+		d.Words[i] = word.Localized(lang)
+	}
+
+	w.Header().Set("content-type", "text/html")
+	return 0, app.rootTpl.Execute(w, d)
+}
+
+const maxAccentText = 1 << 16
+
+func (app *App) handleAccent(w http.ResponseWriter, r *http.Request, p []string) (int, error) {
+	var d AccentPage
+	if r.Method == http.MethodPost {
+		r.Body = http.MaxBytesReader(w, r.Body, maxAccentText)
+		if err := r.ParseForm(); err != nil {
+			return http.StatusRequestEntityTooLarge, nil
+		}
+		d.Text = r.PostForm.Get("text")
+	}
+
+	dct, err := common.GetDict()
+	if err != nil {
+		return 0, err
+	}
+	tokens := accent.Annotate(dct, d.Text)
+
+	switch format := r.FormValue("format"); format {
+	case "plain":
+		w.Header().Set("content-type", "text/plain; charset=utf-8")
+		return 0, accent.Plain(w, tokens)
+	case "json":
+		w.Header().Set("content-type", "application/json")
+		return 0, accent.JSON(w, tokens)
+	case "", "html":
+	default:
+		return http.StatusBadRequest, nil
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := accent.HTML(buf, tokens); err != nil {
+		return 0, err
+	}
+	d.HTML = template.HTML(buf.String())
+
+	w.Header().Set("content-type", "text/html")
+	return 0, app.accentTpl.Execute(w, d)
+}
+
+func (app *App) handleDrill(w http.ResponseWriter, r *http.Request, p []string) (int, error) {
+	q := r.URL.Query()
+	d := DrillPage{Kind: q.Get("kind"), Cases: q.Get("cases"), Persons: q.Get("persons"), Level: q.Get("level")}
+	if d.Kind == "" {
+		d.Kind = "both"
+	}
+
+	var filter dict.Filter
+	o, err := quiz.ParseDrillOptions(d.Kind, d.Cases, d.Persons)
+	if err == nil {
+		filter.Levels, err = dict.ParseLevels(d.Level)
+	}
+
+	dct, err2 := common.GetDict()
+	if err2 != nil {
+		return 0, err2
+	}
+
+	if key := q.Get("q"); err == nil && key != "" {
+		drill, err := quiz.ParseDrill(dct.Words(), key)
+		if err != nil {
+			return http.StatusBadRequest, nil
+		}
+		answer := strings.TrimSpace(q.Get("a"))
+		g := drill.Check(answer)
+		d.Prev = &DrillResult{Drill: drill, Answer: answer, Correct: drill.Correct(g), Edits: g.Edits}
+	}
+
+	if err != nil {
+		d.Error = err.Error()
+	} else {
+		lang, err := app.lang(r)
+		if err != nil {
+			return 0, err
+		}
+		words := app.drillWords(dct, o, filter, lang)
+		rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+		if drill, ok := quiz.RandomDrill(words, o, rnd); ok {
+			d.Drill = drill
+		} else {
+			d.Error = "no words to drill"
+		}
+	}
+
+	w.Header().Set("content-type", "text/html")
+	return 0, app.drillTpl.Execute(w, d)
+}
+
+type DrillResult struct {
+	Drill   *quiz.Drill
+	Answer  string
+	Correct bool
+	Edits   dict.Edits
+}
+
+type DrillPage struct {
+	Kind    string
+	Cases   string
+	Persons string
+	Level   string
+	Error   string
+	Drill   *quiz.Drill
+	Prev    *DrillResult
+}
+
+type FamilyNode struct {
+	Word    *openrussian.Word
+	Current bool
+	Derived []*FamilyNode
+}
+
+type FamilyPage struct {
+	Word   *openrussian.Word
+	Family *FamilyNode
+}
+
+type RootPage struct {
+	Root  string
 	Words []*openrussian.Word
 }
 
+type AccentPage struct {
+	Text string
+	HTML template.HTML
+}
+
+type WordPage struct {
+	Query     string
+	Edits     dict.Edits
+	Audio     string
+	Next      string
+	Lang      string
+	Languages []string
+	Words     []*openrussian.Word
+}
+
 func main() {
-	var addr string
-	var cacheDir string
+	conf, err := common.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var home uint64
 	if !Prod {
-		flag.StringVar(&addr, "l", ":8080", "address to bind to")
+		flag.StringVar(&conf.Web.Listen, "l", conf.Web.Listen, "address to bind to")
 	}
 
-	flag.StringVar(&cacheDir, "c", "", "cache dir, defaults to <XDG default>/goru")
+	flag.StringVar(&conf.CacheDir, "c", conf.CacheDir, "cache dir, defaults to <XDG default>/goru")
+	flag.StringVar(&conf.Language, "lang", conf.Language, "default translation language")
+	flag.Uint64Var(&home, "home", uint64(conf.Web.HomeWord), "id of the word on the home page")
+	flag.IntVar(&conf.Web.CPURate, "cpu", conf.Web.CPURate, "max concurrent image renders, 0 is unlimited")
+	flag.IntVar(&conf.Web.NetRate, "net", conf.Web.NetRate, "max concurrent audio downloads, 0 is unlimited")
+	flag.BoolVar(&conf.History, "history", conf.History, "record searches for 'goru history'")
+	flag.Usage = func() {
+		path, _ := common.ConfigFile()
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\nDefaults are read from %s (or $GORU_CONFIG)\n", path)
+	}
 	flag.Parse()
+	conf.Web.HomeWord = openrussian.ID(home)
 
 	l := log.New(os.Stderr, "", log.Ltime|log.Lmicroseconds)
 	l.Println("initializing")
+	cacheDir := conf.CacheDir
 	if cacheDir == "" {
 		_cacheDir, err := os.UserCacheDir()
 		if err != nil {
@@ -584,8 +938,21 @@ func main() {
 		"absArbitraryAudio": absArbitraryAudio,
 		"absWord":           absWord,
 		"absWordInfo":       absWordInfo,
+		"absFamily":         absFamily,
 		"absImg":            absImg,
 		"absAudio":          absAudio,
+		"absRoot":           absRoot,
+		"hasFamily": func(w *openrussian.Word) bool {
+			dct, err := common.GetDict()
+			return err == nil && (w.DerivedFrom != nil || len(dct.Derived(w)) != 0)
+		},
+		"segment": func(w *openrussian.Word) morph.Segmentation {
+			dct, err := common.GetDict()
+			if err != nil {
+				return nil
+			}
+			return dct.Segment(w)
+		},
 		"genderImg": func(gender interface{}) string {
 			if g, ok := gender.(openrussian.Gender); ok {
 				switch g {
@@ -632,6 +999,26 @@ func main() {
 {{- template "main" . -}}
 {{- template "word-info" (index .Words 0) -}}
 {{- template "js" . -}}
+{{- template "footer" }}`))
+
+	accentTpl := template.Must(tpl.New("accent-page").Parse(`
+{{- template "header" "Accent" -}}
+{{- template "accent" . -}}
+{{- template "footer" }}`))
+
+	drillTpl := template.Must(tpl.New("drill-page").Parse(`
+{{- template "header" "Drill" -}}
+{{- template "drill" . -}}
+{{- template "footer" }}`))
+
+	familyTpl := template.Must(tpl.New("family-page").Parse(`
+{{- template "header" .Word.Word -}}
+{{- template "family" . -}}
+{{- template "footer" }}`))
+
+	rootTpl := template.Must(tpl.New("root-page").Parse(`
+{{- template "header" .Root -}}
+{{- template "root" . -}}
 {{- template "footer" }}`))
 
 	audioCacheDir := filepath.Join(cacheDir, "audio")
@@ -644,9 +1031,10 @@ func main() {
 	os.MkdirAll(arbitImgCacheDir, 0700)
 
 	app := &App{
-		prod: Prod,
-		// cpurate: make(chan struct{}, 3),
-		// netrate: make(chan struct{}, 3),
+		log:         l,
+		prod:        Prod,
+		defaultLang: conf.Language,
+		home:        conf.Web.HomeWord,
 		conf: Config{
 			AudioCacheDir:          audioCacheDir,
 			ImageCacheDir:          imgCacheDir,
@@ -655,10 +1043,27 @@ func main() {
 		},
 		wordsTpl:     tpl,
 		wordTpl:      wordInfoTpl,
+		accentTpl:    accentTpl,
+		drillTpl:     drillTpl,
+		familyTpl:    familyTpl,
+		rootTpl:      rootTpl,
 		homeTpl:      homeTpl,
 		scrapableTpl: scrapableTpl,
 		resultsTpl:   resultsTpl,
 	}
+	if conf.History {
+		dir, err := common.ConfigDir()
+		if err != nil {
+			l.Fatal(err)
+		}
+		app.history = history.NewRecorder(history.File(dir))
+	}
+	if conf.Web.CPURate > 0 {
+		app.cpurate = make(chan struct{}, conf.Web.CPURate)
+	}
+	if conf.Web.NetRate > 0 {
+		app.netrate = make(chan struct{}, conf.Web.NetRate)
+	}
 
 	// build our own mux since https://github.com/golang/go/issues/21955
 	// is a ridiculous issue that should have been fixed in 2017...
@@ -688,9 +1093,14 @@ func main() {
 		l.Fatal(err)
 	}
 	l.Println("loaded dictionary")
-	d.InitEnglishFuzzIndex()
-	l.Println("initialized english index")
+	if !d.HasLanguage(app.defaultLang) {
+		l.Fatalf("unknown language '%s', available: %s", app.defaultLang, strings.Join(d.Languages(), ", "))
+	}
+	for _, lang := range d.Languages() {
+		d.InitTranslationFuzzIndex(lang)
+		l.Printf("initialized %s index", lang)
+	}
 	d.InitRussianFuzzIndex()
 	l.Println("initialized russian index")
-	l.Fatal(run(s, addr))
+	l.Fatal(run(s, conf.Web))
 }
@@ -1,4 +1,4 @@
-// +build prod
+// +build prod,!noweb
 
 package main
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/coreos/go-systemd/activation"
+	"github.com/frizinak/goru/common"
 	"github.com/frizinak/gotls/tls"
 )
 
@@ -37,8 +38,23 @@ func domainKey() *ecdsa.PrivateKey {
 	return c
 }
 
-func run(s *tls.Server, addr string) error {
-	listeners := []interface{}{HTTPAddr, HTTPSAddr}
+func or(v, def string) string {
+	if v != "" {
+		return v
+	}
+	return def
+}
+
+func orList(v, def []string) []string {
+	if len(v) != 0 {
+		return v
+	}
+	return def
+}
+
+func run(s *tls.Server, conf common.WebConfig) error {
+	c := conf.TLS
+	listeners := []interface{}{or(c.HTTPAddr, HTTPAddr), or(c.HTTPSAddr, HTTPSAddr)}
 	_listeners, _ := activation.Listeners()
 	for i := range _listeners {
 		listeners[i] = _listeners[i]
@@ -46,12 +62,12 @@ func run(s *tls.Server, addr string) error {
 	return s.StartCertified(
 		listeners[1],
 		listeners[0],
-		ACMEDir,
-		Domains,
-		Contact,
+		or(c.ACMEDir, ACMEDir),
+		orList(c.Domains, Domains),
+		orList(c.Contact, Contact),
 		time.Hour*24*30,
 		accountKey(),
 		domainKey(),
-		CacheDir,
+		or(c.CacheDir, CacheDir),
 	)
 }
@@ -1,3 +1,5 @@
+// +build !noweb
+
 package main
 
 import (
@@ -44,8 +46,13 @@ var mainTpl = nonl(`{{- define "trans" -}}
 
 {{- define "word-info" -}}
 <div class="meta">
+{{- with segment . }}<p class="morphemes">{{ template "segmentation" . }}</p>{{ end -}}
+{{- if hasFamily . }}<p><a href="{{ absFamily . }}">word family</a></p>{{ end -}}
 {{- with .AdjInfo -}}
 	<table class="adj">
+		{{- if .Incomparable -}}
+		<tr><td>Incomparable</td><td></td></tr>
+		{{- end -}}
 		{{- with .Comparative -}}
 		<tr><td>Comparative</td><td>{{ .Unstressed }}</td>{{ template "arb-img" . }}</tr>
 		{{- end -}}
@@ -89,6 +96,16 @@ var mainTpl = nonl(`{{- define "trans" -}}
 		<tr><td></td><td></td></tr>
 	</table>
 {{- end -}}
+{{- with .Sentences -}}
+	<table class="sentences">
+	{{- range . -}}
+		<tr><td>
+			<p>{{ .Russian }}</p>
+			{{- range .Translations }}<p class="sentence-tl">{{ .Translation }}</p>{{ end -}}
+		</td></tr>
+	{{- end -}}
+	</table>
+{{- end -}}
 </div>
 {{- end -}}
 
@@ -107,7 +124,7 @@ var mainTpl = nonl(`{{- define "trans" -}}
 
 {{- define "word" -}}
 <td class="smol">
-{{- if or .AdjInfo .VerbInfo -}}
+{{- if or .AdjInfo .VerbInfo .Sentences -}}
 <a href="{{ absWordInfo . }}">{{- template "wordStr" . -}}</a>
 {{- else -}}
 {{- template "wordStr" . -}}
@@ -126,6 +143,7 @@ var mainTpl = nonl(`{{- define "trans" -}}
 </td>
 <td class="smol">
 {{- .WordType -}}
+{{- with .Usage }}<div class="usage">{{ . }}</div>{{ end -}}
 </td>
 <td class="smol">
 {{- if .DerivedFrom }}<a href="{{ absWord .DerivedFrom }}">{{ .DerivedFrom.Word }}</a>{{ end -}}
@@ -178,6 +196,10 @@ var mainTpl = nonl(`{{- define "trans" -}}
 		form                   { position: relative; }
 		form input             { min-height: 2em; font-size: 2em; background-color: #333; color: #fff; outline: none; border: 1px solid #ccc; padding: 20px; width: 89%; }
 		form .submit           { position: absolute; top: 0; right: 0; width: 10%; margin-left: 1%; }
+		.langs                 { text-align: right; margin-bottom: 10px; }
+		.lang                  { margin-left: 10px; }
+		.lang.active           { color: #fff; text-decoration: none; }
+		.usage                 { color: #faa; font-size: 0.8em; }
 		.edits                 { font-size: 2em; display: inline-block; width: auto; border: 3px #800 solid; padding: 2px 1em; }
 		.edit                  { padding: 5px 0; }
 		.edit.h                { display: none; }
@@ -189,8 +211,36 @@ var mainTpl = nonl(`{{- define "trans" -}}
 		.meta .gender          { width: 16px; }
 		.meta td:first-child   {  }
 		.meta td               { width: auto; height: 2em; padding: 0 20px 0 0; }
+		.meta .sentences td    { padding-bottom: 1em; }
+		.meta .sentence-tl     { color: #aaa; }
 		.meta td.img-container img { max-height: 150%; width: auto; }
 		img                    { image-rendering: crisp-edges; }
+		.accent textarea       { width: 89%; min-height: 10em; font-size: 1.5em; background-color: #333; color: #fff; border: 1px solid #ccc; padding: 20px; }
+		.accent .submit        { font-size: 2em; }
+		.accented              { margin-top: 40px; font-size: 1.5em; line-height: 1.8em; }
+		.accent .ambiguous     { border-bottom: 2px dotted #fa0; cursor: help; }
+		.accent .unknown       { color: #888; }
+		.accent .legend        { margin-top: 20px; color: #aaa; }
+		.drill .prompt         { font-size: 2em; margin: 20px 0; }
+		.drill .val            { width: 60%; }
+		.drill .prev           { font-size: 1.5em; margin: 20px 0; }
+		.drill .correct        { color: #080; }
+		.drill .wrong          { color: #c00; }
+		.drill .edits          { font-size: 1em; }
+		.drill .options        { margin-top: 40px; color: #aaa; }
+		.family ul             { list-style: none; padding-left: 30px; line-height: 2em; }
+		.family > ul           { padding-left: 0; }
+		.family .current > a   { font-weight: bold; }
+		.family .type          { color: #aaa; }
+		.family .tl            { margin-left: 10px; }
+		.morphemes .prefix     { color: #48c; }
+		.morphemes .root       { font-weight: bold; }
+		.morphemes .suffix     { color: #c84; }
+		.morphemes .ending     { color: #888; }
+		.morphemes .postfix    { color: #8a4; }
+		.morphemes .dash       { color: #ccc; }
+		.root-words td         { padding-right: 20px; }
+		.root-words .type      { color: #aaa; }
 		}
 	</style>
 </head>
@@ -223,7 +273,121 @@ No results
 {{- end -}}
 {{- end -}}
 
+{{- define "accent" -}}
+<div class="accent">
+<form method="post" action="/accent">
+<textarea name="text" placeholder="Текст">{{ .Text }}</textarea>
+<input type="submit" class="submit" value=">" />
+</form>
+{{- with .HTML -}}
+<div class="accented">{{ . }}</div>
+<div class="legend"><span class="ambiguous">ambiguous</span> <span class="unknown">unknown</span></div>
+{{- end -}}
+</div>
+{{- end -}}
+
+{{- define "drill" -}}
+<div class="drill">
+{{- with .Prev -}}
+<div class="prev">
+<span class="{{ if .Correct }}correct{{ else }}wrong{{ end }}">{{ .Drill.Prompt }}:</span> {{ if .Correct -}}
+{{ .Answer }}
+{{- else -}}
+{{- with .Edits -}}
+<span class="edits">
+{{- range . -}}
+<span class="edit {{ editType .Type }}">{{ . }}</span>
+{{- end -}}
+</span>
+{{- else -}}
+<s>{{ .Answer }}</s>
+{{- end }} {{ range $i, $a := .Drill.Answers }}{{ if $i }}, {{ end }}{{ $a }}{{ end -}}
+{{- end -}}
+</div>
+{{- end -}}
+{{- with .Error }}<div class="error">{{ . }}</div>{{ end -}}
+{{- with .Drill -}}
+<form method="get" action="/drill">
+<div class="prompt">{{ .Prompt }}</div>
+<input type="hidden" name="q" value="{{ .Key }}" />
+<input type="hidden" name="kind" value="{{ $.Kind }}" />
+<input type="hidden" name="cases" value="{{ $.Cases }}" />
+<input type="hidden" name="persons" value="{{ $.Persons }}" />
+<input type="hidden" name="level" value="{{ $.Level }}" />
+<input type="text" name="a" class="val" autofocus autocomplete="off" />
+<input type="submit" class="submit" value=">" />
+</form>
+{{- end -}}
+<form method="get" action="/drill" class="options">
+<select name="kind">
+<option{{ if eq .Kind "both" }} selected{{ end }}>both</option>
+<option{{ if eq .Kind "decline" }} selected{{ end }}>decline</option>
+<option{{ if eq .Kind "conjugate" }} selected{{ end }}>conjugate</option>
+</select>
+<input type="text" name="cases" value="{{ .Cases }}" placeholder="cases: inst,prep" />
+<input type="text" name="persons" value="{{ .Persons }}" placeholder="persons: я,мы" />
+<input type="text" name="level" value="{{ .Level }}" placeholder="levels: A1,A2" />
+<input type="submit" value="apply" />
+</form>
+</div>
+{{- end -}}
+
+{{- define "family-node" -}}
+<li{{ if .Current }} class="current"{{ end }}>
+<a href="{{ absWordInfo .Word }}">{{ stressednc .Word }}</a> <span class="type">{{ .Word.WordType }}</span>
+{{- with .Word.Translations }} <span class="tl">{{ range $i, $t := . }}{{ if $i }}; {{ end }}{{ $t.Translation }}{{ end }}</span>{{ end -}}
+{{- with .Derived -}}
+<ul>
+{{- range . }}{{ template "family-node" . }}{{ end -}}
+</ul>
+{{- end -}}
+</li>
+{{- end -}}
+
+{{- define "family" -}}
+<div class="family">
+<h1><a href="{{ absWord .Word }}">{{ .Word.Word }}</a></h1>
+<ul>{{ template "family-node" .Family }}</ul>
+</div>
+{{- end -}}
+
+{{- define "segmentation" -}}
+<span class="morphemes">
+{{- range $i, $m := . -}}
+{{- if $i }}<span class="dash">-</span>{{ end -}}
+{{- if eq $m.Kind.String "root" -}}
+<a class="root" href="{{ absRoot $m.Text }}" title="root">{{ $m.Stressed }}</a>
+{{- else -}}
+<span class="{{ $m.Kind }}" title="{{ $m.Kind }}">{{ $m.Stressed }}</span>
+{{- end -}}
+{{- end -}}
+</span>
+{{- end -}}
+
+{{- define "root" -}}
+<div class="root-words">
+<h1>{{ .Root }}</h1>
+<table>
+{{- range .Words -}}
+<tr>
+<td><a href="{{ absWordInfo . }}">{{ stressednc . }}</a></td>
+<td>{{ template "segmentation" segment . }}</td>
+<td class="type">{{ .WordType }}</td>
+<td>{{ range $i, $t := .Translations }}{{ if $i }}; {{ end }}{{ $t.Translation }}{{ end }}</td>
+</tr>
+{{- end -}}
+</table>
+</div>
+{{- end -}}
+
 {{- define "main" -}}
+<div class="langs">
+<a href="/accent" class="lang">accent</a>
+<a href="/drill" class="lang">drill</a>
+{{- range .Languages -}}
+<a href="/l/{{ . }}" class="lang{{ if eq . $.Lang }} active{{ end }}">{{ . }}</a>
+{{- end -}}
+</div>
 <div class="input">
 <form>
 <input type="text"   class="val"    value="{{ .Query }}" placeholder="Слово | Word" />
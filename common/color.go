@@ -25,13 +25,11 @@ func (c clr) str() string {
 	if len(c.ansi) == 0 {
 		return ""
 	}
-	s := make([]string, 1, 2+len(c.ansi))
-	s[0] = "\033["
+	s := make([]string, 0, len(c.ansi))
 	for _, v := range c.ansi {
 		s = append(s, strconv.Itoa(v))
 	}
-	s = append(s, "m")
-	return strings.Join(s, "")
+	return "\033[" + strings.Join(s, ";") + "m"
 }
 
 func (c clr) String() string {
@@ -42,8 +40,9 @@ func (c clr) String() string {
 		if len(*c.q) != 0 {
 			*c.q = (*c.q)[:len(*c.q)-1]
 			if len(*c.q) != 0 {
-				l := (*c.q)[len(*c.q)-1].str()
-				return l
+				// reset first as the popped style might have set
+				// attributes (e.g.: bold) the previous one does not.
+				return "\033[0m" + (*c.q)[len(*c.q)-1].str()
 			}
 		}
 		return "\033[0m"
@@ -2,7 +2,10 @@ package common
 
 import (
 	"bytes"
+	"fmt"
 	htmltpl "html/template"
+	"os"
+	"strings"
 	"text/template"
 
 	"github.com/frizinak/goru/data"
@@ -10,34 +13,65 @@ import (
 	"github.com/frizinak/goru/openrussian"
 )
 
-const tplStr = `{{- define "trans" }}  {{ .Translation }}
-{{ if .Info }}  {{ clrRed }} {{- .Info -}} {{ clrPop }}
+const tplStr = `{{- define "trans" }}{{ wrap 2 .Translation }}
+{{ if .Info }}{{ clrRed }} {{- wrap 2 .Info -}} {{ clrPop }}
 {{ end -}}
-{{ if .Example }}  {{ .Example }}
-{{ if .ExampleTranslation }}  {{ .ExampleTranslation}}
+{{ if .Example }}{{ wrap 2 .Example }}
+{{ if .ExampleTranslation }}{{ wrap 2 .ExampleTranslation }}
 {{ end -}}
 {{ end }}
 {{- end -}}
 
 {{- define "gender" -}}{{ genderSymbol . }}{{- end -}}
 
+{{- define "sentence" }}{{ clrBlue }} {{- wrap 2 .Russian -}} {{ clrPop }}
+{{ range .Translations }}{{ wrap 2 .Translation }}
+{{ end }}
+{{- end -}}
+
+{{- define "sentences" -}}
+{{ with .Sentences }}
+{{ range . }}{{ template "sentence" . }}{{ end }}
+{{- end -}}
+{{- end -}}
+
+{{- define "wordDetail" -}}{{- end -}}
+
 {{- define "word" -}}
 {{ template "wordStr" . }}
 {{- if .NounInfo }} {{ template "gender" .NounInfo.Gender }}{{ end }} {{ .WordType -}}
+{{ with .AdjInfo }}{{ if .Incomparable }} incomparable{{ end }}{{ end -}}
+{{ if .Usage }} {{ clrRed }}({{ .Usage }}){{ clrPop }}{{ end -}}
 {{ if .DerivedFrom }} [{{ derived . }}]{{ end }}
 {{- range .Translations }}
 {{ template "trans" . }}{{ end }}
+{{- template "wordDetail" . }}
 {{- end -}}
 
 {{- define "wordStr" -}}
 {{ clrGreen }} {{- stressed . -}} {{ clrPop }}
 {{- end -}}
 
+{{- define "declension" -}}
+{{ with .NounInfo }}{{ table (nounTable .) }}{{ end -}}
+{{ with .AdjInfo }}{{ table (adjTable .) }}{{ table (comparisonTable .) }}{{ end -}}
+{{- end -}}
+
+{{- define "conjugation" -}}
+{{ with .VerbInfo }}{{ table (conjugationTable .) }}{{ table (verbFormsTable .) }}{{ end -}}
+{{- end -}}
+
+{{- define "grammar" -}}
+{{ template "declension" . }}{{ template "conjugation" . }}
+{{- end -}}
+
 {{- range . }}{{ template "word" . }}
 {{ end }}`
 
 var dct *dict.Dict
+var detailed bool
 var tpl *template.Template
+var plaintpl *template.Template
 var httpl *htmltpl.Template
 
 func GetDict() (*dict.Dict, error) {
@@ -54,17 +88,56 @@ func GetDict() (*dict.Dict, error) {
 	return dct, nil
 }
 
-func getTplFuncs() template.FuncMap {
+// GetDetailDict returns a dictionary that includes declensions and
+// conjugations. Builds that embed the stripped database (noweb) load it from
+// path instead, see Config.DatabaseFile. Afterwards GetDict returns the same
+// dictionary.
+func GetDetailDict(path string) (*dict.Dict, error) {
+	if data.Detailed {
+		return GetDict()
+	}
+	if detailed {
+		return dct, nil
+	}
+
+	words, err := openrussian.LoadGOB(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf(
+				"this build does not include declensions and conjugations, copy data/data/db.web.gob to %s",
+				path,
+			)
+		}
+		return nil, err
+	}
+
+	dct, detailed = dict.New(words), true
+	return dct, nil
+}
+
+func getTplFuncs(color bool) template.FuncMap {
 	_clrs := make(clrs, 0)
 	clrs := &_clrs
-	clrRed := func() clr { return clrs.Get(31) }
-	clrGreen := func() clr { return clrs.Get(32) }
-	clrYellow := func() clr { return clrs.Get(33) }
-	clrBlue := func() clr { return clrs.Get(34) }
-	clrMagenta := func() clr { return clrs.Get(35) }
-	clrCyan := func() clr { return clrs.Get(36) }
-	clrGray := func() clr { return clrs.Get(37) }
-	clrPop := func() clr { return clrs.Pop() }
+	get := func(name string) clr {
+		if !color {
+			return clr{}
+		}
+		return clrs.Get(theme.Get(name)...)
+	}
+	clrRed := func() clr { return get("red") }
+	clrGreen := func() clr { return get("green") }
+	clrYellow := func() clr { return get("yellow") }
+	clrBlue := func() clr { return get("blue") }
+	clrMagenta := func() clr { return get("magenta") }
+	clrCyan := func() clr { return get("cyan") }
+	clrGray := func() clr { return get("gray") }
+	clrStress := func() clr { return get("stress") }
+	clrPop := func() clr {
+		if !color {
+			return clr{}
+		}
+		return clrs.Pop()
+	}
 
 	unstressed := func(w *openrussian.Word) stringer {
 		return strStringer(w.Word)
@@ -74,8 +147,8 @@ func getTplFuncs() template.FuncMap {
 		return strStringer(w.Stressed.Parse().String())
 	}
 
-	stressed := func(w *openrussian.Word) stringer {
-		p := w.Stressed.Parse()
+	stressedForm := func(s openrussian.Stressed) stringer {
+		p := s.Parse()
 		list := make(stringList, 0, 5)
 		space := strStringer(" ")
 		for _, w := range p {
@@ -86,7 +159,7 @@ func getTplFuncs() template.FuncMap {
 			list = append(
 				list,
 				strStringer(w.Prefix),
-				clrYellow(),
+				clrStress(),
 				strStringer(w.Stress),
 				clrPop(),
 				strStringer(w.Suffix),
@@ -98,8 +171,17 @@ func getTplFuncs() template.FuncMap {
 		}
 		return list
 	}
+	if !color {
+		stressedForm = func(s openrussian.Stressed) stringer {
+			return strStringer(s.String())
+		}
+	}
 
-	return template.FuncMap{
+	stressed := func(w *openrussian.Word) stringer {
+		return stressedForm(w.Stressed)
+	}
+
+	funcs := template.FuncMap{
 		"derived": func(w *openrussian.Word) stringer {
 			l := dict.DerivedList(w)
 			s := make(stringList, len(l)*2)
@@ -124,18 +206,30 @@ func getTplFuncs() template.FuncMap {
 
 			return "?"
 		},
-		"clrRed":     clrRed,
-		"clrGreen":   clrGreen,
-		"clrYellow":  clrYellow,
-		"clrBlue":    clrBlue,
-		"clrMagenta": clrMagenta,
-		"clrCyan":    clrCyan,
-		"clrGray":    clrGray,
-		"clrPop":     clrPop,
-		"stressed":   stressed,
-		"unstressed": unstressed,
-		"stressednc": stressednc,
+		"clrRed":       clrRed,
+		"clrGreen":     clrGreen,
+		"clrYellow":    clrYellow,
+		"clrBlue":      clrBlue,
+		"clrMagenta":   clrMagenta,
+		"clrCyan":      clrCyan,
+		"clrGray":      clrGray,
+		"clrStress":    clrStress,
+		"clrPop":       clrPop,
+		"stressed":     stressed,
+		"unstressed":   unstressed,
+		"stressednc":   stressednc,
+		"stressedForm": stressedForm,
+		"wrap": func(indent int, v interface{}) string {
+			pad := strings.Repeat(" ", indent)
+			return Wrap(pad+fmt.Sprint(v), width, "  ")
+		},
+		"table": writeTable,
+	}
+	form := func(s openrussian.Stressed) string { return stressedForm(s).String() }
+	for name, fn := range tableFuncs(form) {
+		funcs[name] = fn
 	}
+	return funcs
 }
 
 func GetHTMLTpl() (*htmltpl.Template, error) {
@@ -144,7 +238,7 @@ func GetHTMLTpl() (*htmltpl.Template, error) {
 	}
 
 	var err error
-	httpl, err = htmltpl.New("tpls").Funcs(htmltpl.FuncMap(getTplFuncs())).Parse(tplStr)
+	httpl, err = htmltpl.New("tpls").Funcs(htmltpl.FuncMap(getTplFuncs(true))).Parse(tplStr)
 
 	return httpl, err
 }
@@ -155,7 +249,20 @@ func GetTpl() (*template.Template, error) {
 	}
 
 	var err error
-	tpl, err = template.New("tpls").Funcs(getTplFuncs()).Parse(tplStr)
+	tpl, err = template.New("tpls").Funcs(getTplFuncs(true)).Parse(tplStr)
 
 	return tpl, err
 }
+
+// GetPlainTpl returns the same templates as GetTpl without any ansi escape
+// sequences.
+func GetPlainTpl() (*template.Template, error) {
+	if plaintpl != nil {
+		return plaintpl, nil
+	}
+
+	var err error
+	plaintpl, err = template.New("tpls").Funcs(getTplFuncs(false)).Parse(tplStr)
+
+	return plaintpl, err
+}
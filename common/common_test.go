@@ -0,0 +1,116 @@
+package common
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+func TestGrammarTpl(t *testing.T) {
+	tpl, err := GetPlainTpl()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &openrussian.Word{
+		Word:     "новый",
+		Stressed: "но'вый",
+		AdjInfo: &openrussian.AdjInfo{
+			F: &openrussian.AdjGenderInfo{
+				Gender: openrussian.F,
+				Short:  openrussian.StressedList{"нова'"},
+				Decl: &openrussian.Declension{
+					Nom:  openrussian.StressedList{"но'вая"},
+					Inst: openrussian.StressedList{"но'вой", "но'вою"},
+				},
+			},
+			Comparative: openrussian.StressedList{"нове'е"},
+		},
+		VerbInfo: &openrussian.VerbInfo{
+			Conjugation: &openrussian.Conjugation{Sg1: "пишу'"},
+			PastM:       "писа'л",
+			PastF:       "писа'ла",
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := tpl.ExecuteTemplate(buf, "grammar", w); err != nil {
+		t.Fatal(err)
+	}
+	exp := `
+         feminine
+  nom    но́вая
+  gen
+  dat
+  acc
+  inst   но́вой, но́вою
+  prep
+  short  нова́
+
+  comparative  нове́е
+
+  я  пишу́
+
+  past  писа́л, писа́ла
+`
+	if got := buf.String(); got != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, got)
+	}
+
+	buf.Reset()
+	if err := tpl.ExecuteTemplate(buf, "grammar", &openrussian.Word{}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for a word without grammar, got %q", buf.String())
+	}
+}
+
+func TestGrammarTplNoun(t *testing.T) {
+	master, err := GetPlainTpl()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl, err := master.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl.Funcs(NoStressFuncs())
+
+	w := &openrussian.Word{
+		Word:     "стол",
+		Stressed: "сто'л",
+		NounInfo: &openrussian.NounInfo{
+			Singular: &openrussian.Declension{Nom: openrussian.StressedList{"сто'л"}},
+			Plural:   &openrussian.Declension{Nom: openrussian.StressedList{"столы'"}},
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := tpl.ExecuteTemplate(buf, "declension", w); err != nil {
+		t.Fatal(err)
+	}
+	exp := `
+        singular  plural
+  nom   стол      столы
+  gen
+  dat
+  acc
+  inst
+  prep
+`
+	if got := buf.String(); got != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, got)
+	}
+
+	w.NounInfo.SingularOnly = true
+	buf.Reset()
+	if err := master.ExecuteTemplate(buf, "declension", w); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.Contains(got, "сто́л") || strings.Contains(got, "plural") {
+		t.Errorf("expected a stressed singular only table, got:\n%s", got)
+	}
+}
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+// Config holds the defaults of goru and goruweb.
+// Flags override these values.
+type Config struct {
+	// Results is the max amount of search results.
+	Results uint `json:"results"`
+	// Language is the translation language.
+	Language string `json:"language"`
+	// Color is one of auto, always or never.
+	Color string `json:"color"`
+	// Theme is the name of a builtin theme or one from themes.json.
+	Theme string `json:"theme"`
+	// History enables recording lookups for 'goru history'.
+	History bool `json:"history"`
+	// CacheDir is where goruweb stores audio and images,
+	// defaults to <XDG cache>/goru.
+	CacheDir string `json:"cache_dir"`
+	// Database is the path to db.web.gob, only used by builds that embed
+	// the stripped database (noweb), defaults to <XDG config>/goru/db.web.gob.
+	Database string `json:"database"`
+
+	Web WebConfig `json:"web"`
+}
+
+type WebConfig struct {
+	// Listen is the address to bind to in non-prod builds.
+	Listen string `json:"listen"`
+	// Debug is the pprof address in non-prod builds, empty disables it.
+	Debug string `json:"debug"`
+	// HomeWord is the id of the word shown on the home page.
+	HomeWord openrussian.ID `json:"home_word"`
+	// CPURate and NetRate limit the amount of concurrent image renders and
+	// audio downloads, 0 is unlimited.
+	CPURate int `json:"cpu_rate"`
+	NetRate int `json:"net_rate"`
+
+	TLS TLSConfig `json:"tls"`
+}
+
+// TLSConfig overrides the compile-time settings of prod builds,
+// empty values keep them.
+type TLSConfig struct {
+	HTTPAddr  string   `json:"http_addr"`
+	HTTPSAddr string   `json:"https_addr"`
+	ACMEDir   string   `json:"acme_dir"`
+	Domains   []string `json:"domains"`
+	Contact   []string `json:"contact"`
+	CacheDir  string   `json:"cache_dir"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Results:  3,
+		Language: openrussian.DefaultLanguage,
+		Color:    "auto",
+		Theme:    "default",
+		Web: WebConfig{
+			Listen:   ":8080",
+			Debug:    ":6060",
+			HomeWord: 33002,
+		},
+	}
+}
+
+// ConfigDir returns <XDG config>/goru.
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "goru"), nil
+}
+
+// ConfigFile returns $GORU_CONFIG or <XDG config>/goru/config.json.
+func ConfigFile() (string, error) {
+	if path := os.Getenv("GORU_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// DatabaseFile returns the path of the detailed database (db.web.gob) used
+// by noweb builds.
+func (c Config) DatabaseFile() (string, error) {
+	if c.Database != "" {
+		return c.Database, nil
+	}
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "db.web.gob"), nil
+}
+
+// LoadConfig reads ConfigFile on top of DefaultConfig.
+// A missing file is not an error.
+func LoadConfig() (Config, error) {
+	c := DefaultConfig()
+	path, err := ConfigFile()
+	if err != nil {
+		return c, nil
+	}
+
+	d, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return c, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(d))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,180 @@
+package common
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+// FormFunc renders a stressed form as a table cell.
+type FormFunc func(openrussian.Stressed) string
+
+func formList(form FormFunc, l openrussian.StressedList) string {
+	s := make([]string, len(l))
+	for i := range l {
+		s[i] = form(l[i])
+	}
+	return strings.Join(s, ", ")
+}
+
+var cases = []string{"nom", "gen", "dat", "acc", "inst", "prep"}
+
+func declensionCases(d *openrussian.Declension) []openrussian.StressedList {
+	if d == nil {
+		return make([]openrussian.StressedList, len(cases))
+	}
+	return []openrussian.StressedList{d.Nom, d.Gen, d.Dat, d.Acc, d.Inst, d.Prep}
+}
+
+// DeclensionTable creates a Table with a row per case and a column per
+// declension.
+func DeclensionTable(form FormFunc, header []string, decls ...*openrussian.Declension) Table {
+	t := make(Table, 0, len(cases)+1)
+	t = append(t, append([]string{""}, header...))
+	cols := make([][]openrussian.StressedList, len(decls))
+	for i, d := range decls {
+		cols[i] = declensionCases(d)
+	}
+	for i, c := range cases {
+		row := make([]string, 1, len(decls)+1)
+		row[0] = c
+		for _, col := range cols {
+			row = append(row, formList(form, col[i]))
+		}
+		t = append(t, row)
+	}
+	return t
+}
+
+// NounTable creates the declension table of a noun, without the singular or
+// plural column if it has no such forms.
+func NounTable(form FormFunc, n *openrussian.NounInfo) Table {
+	var header []string
+	var decls []*openrussian.Declension
+	if !n.PluralOnly && n.Singular != nil {
+		header, decls = append(header, "singular"), append(decls, n.Singular)
+	}
+	if !n.SingularOnly && n.Plural != nil {
+		header, decls = append(header, "plural"), append(decls, n.Plural)
+	}
+	if len(decls) == 0 {
+		return nil
+	}
+	return DeclensionTable(form, header, decls...)
+}
+
+// AdjTable creates the declension table of an adjective with a column per
+// gender and a row with the short forms.
+func AdjTable(form FormFunc, a *openrussian.AdjInfo) Table {
+	var header []string
+	var decls []*openrussian.Declension
+	var short []string
+	hasShort := false
+	for _, g := range []*openrussian.AdjGenderInfo{a.M, a.F, a.N, a.Pl} {
+		if g == nil {
+			continue
+		}
+		header = append(header, g.Gender.String())
+		decls = append(decls, g.Decl)
+		short = append(short, formList(form, g.Short))
+		hasShort = hasShort || len(g.Short) != 0
+	}
+	if len(decls) == 0 {
+		return nil
+	}
+	t := DeclensionTable(form, header, decls...)
+	if hasShort {
+		t = append(t, append([]string{"short"}, short...))
+	}
+	return t
+}
+
+// ComparisonTable creates a Table with the comparative and superlative of
+// an adjective.
+func ComparisonTable(form FormFunc, a *openrussian.AdjInfo) Table {
+	t := make(Table, 0, 2)
+	if len(a.Comparative) != 0 {
+		t = append(t, []string{"comparative", formList(form, a.Comparative)})
+	}
+	if len(a.Superlative) != 0 {
+		t = append(t, []string{"superlative", formList(form, a.Superlative)})
+	}
+	return t
+}
+
+var persons = []string{"я", "ты", "он/она/оно", "мы", "вы", "они"}
+
+// ConjugationTable creates a Table with a row per person.
+func ConjugationTable(form FormFunc, v *openrussian.VerbInfo) Table {
+	t := make(Table, 0, len(persons))
+	if c := v.Conjugation; c != nil {
+		for i, f := range c.Forms() {
+			if f != "" {
+				t = append(t, []string{persons[i], form(f)})
+			}
+		}
+	}
+	return t
+}
+
+// VerbFormsTable creates a Table with the imperative, past tense and
+// participles of a verb.
+func VerbFormsTable(form FormFunc, v *openrussian.VerbInfo) Table {
+	t := make(Table, 0, 6)
+	add := func(label string, l ...openrussian.Stressed) {
+		n := make(openrussian.StressedList, 0, len(l))
+		for _, s := range l {
+			if s != "" {
+				n = append(n, s)
+			}
+		}
+		if len(n) != 0 {
+			t = append(t, []string{label, formList(form, n)})
+		}
+	}
+	add("imperative", v.ImperativeSg, v.ImperativePl)
+	add("past", v.PastM, v.PastF, v.PastN, v.PastPl)
+	for _, p := range []struct {
+		label string
+		w     *openrussian.Word
+	}{
+		{"active present participle", v.ActivePresent},
+		{"active past participle", v.ActivePast},
+		{"passive present participle", v.PassivePresent},
+		{"passive past participle", v.PassivePast},
+	} {
+		if p.w != nil {
+			add(p.label, p.w.Stressed)
+		}
+	}
+	return t
+}
+
+// tableFuncs returns the template funcs that build the grammar tables.
+func tableFuncs(form FormFunc) template.FuncMap {
+	return template.FuncMap{
+		"nounTable":        func(n *openrussian.NounInfo) Table { return NounTable(form, n) },
+		"adjTable":         func(a *openrussian.AdjInfo) Table { return AdjTable(form, a) },
+		"comparisonTable":  func(a *openrussian.AdjInfo) Table { return ComparisonTable(form, a) },
+		"conjugationTable": func(v *openrussian.VerbInfo) Table { return ConjugationTable(form, v) },
+		"verbFormsTable":   func(v *openrussian.VerbInfo) Table { return VerbFormsTable(form, v) },
+	}
+}
+
+// NoStressFuncs returns template funcs that build the grammar tables without
+// stress marks, use them with Funcs on a clone of the templates.
+func NoStressFuncs() template.FuncMap {
+	return tableFuncs(func(s openrussian.Stressed) string { return s.Unstressed() })
+}
+
+// writeTable renders t preceded by an empty line, nothing if it is empty.
+func writeTable(t Table) (string, error) {
+	if len(t) == 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	b.WriteString("\n")
+	err := t.Write(&b, "  ", width)
+	return b.String(), err
+}
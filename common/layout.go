@@ -0,0 +1,215 @@
+package common
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+// cyrillic is ambiguous in east asian locales, always count it as 1 cell.
+var cells = &runewidth.Condition{EastAsianWidth: false}
+
+// RuneWidth returns the amount of terminal cells r occupies.
+// Combining marks (e.g.: the stress mark U+0301) occupy none.
+func RuneWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	return cells.RuneWidth(r)
+}
+
+// ansi tracks whether we're inside an ansi escape sequence.
+type ansi bool
+
+func (a *ansi) skip(r rune) bool {
+	switch {
+	case bool(*a):
+		*a = ansi(r < 0x40 || r > 0x7e || r == '[')
+		return true
+	case r == 0x1b:
+		*a = true
+		return true
+	}
+	return false
+}
+
+// Width returns the amount of terminal cells s occupies, ignoring ansi escape
+// sequences and combining marks.
+func Width(s string) int {
+	n := 0
+	var esc ansi
+	for _, r := range s {
+		if esc.skip(r) {
+			continue
+		}
+		n += RuneWidth(r)
+	}
+	return n
+}
+
+// hardWrap cuts s in lines of at most w cells, keeping ansi escape
+// sequences and combining marks with the preceding character.
+func hardWrap(s string, w int) []string {
+	if w < 1 {
+		return []string{s}
+	}
+	lines := make([]string, 0, 1)
+	var cur strings.Builder
+	n := 0
+	var esc ansi
+	for _, r := range s {
+		if !esc.skip(r) {
+			rw := RuneWidth(r)
+			if n+rw > w && n != 0 {
+				lines = append(lines, cur.String())
+				cur.Reset()
+				n = 0
+			}
+			n += rw
+		}
+		cur.WriteRune(r)
+	}
+	return append(lines, cur.String())
+}
+
+// Truncate cuts s to at most w cells.
+func Truncate(s string, w int) string {
+	if w < 1 || Width(s) <= w {
+		return s
+	}
+	l := hardWrap(s, w)[0]
+	if strings.ContainsRune(l, 0x1b) {
+		l += "\033[0m"
+	}
+	return l
+}
+
+// Wrap word wraps every line of s at w cells. Continuation lines get the
+// same leading whitespace as the line they belong to plus indent
+// (hanging indentation). Words longer than a line are broken up.
+// A w < 1 disables wrapping.
+func Wrap(s string, w int, indent string) string {
+	if w < 1 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = wrapLine(l, w, indent)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func wrapLine(s string, w int, indent string) string {
+	if Width(s) <= w {
+		return s
+	}
+
+	lead := s[:len(s)-len(strings.TrimLeft(s, " "))]
+	hang := lead + indent
+	if Width(hang)*2 > w {
+		hang = ""
+	}
+
+	var b strings.Builder
+	b.WriteString(lead)
+	n := Width(lead)
+	start := n
+	for _, word := range strings.Fields(s) {
+		ww := Width(word)
+		if n != start && n+1+ww > w {
+			b.WriteString("\n")
+			b.WriteString(hang)
+			n = Width(hang)
+			start = n
+		}
+		if n != start {
+			b.WriteString(" ")
+			n++
+		}
+		if n+ww <= w {
+			b.WriteString(word)
+			n += ww
+			continue
+		}
+
+		parts := hardWrap(word, w-n)
+		b.WriteString(parts[0])
+		n += Width(parts[0])
+		if len(parts) == 1 {
+			continue
+		}
+		rest := strings.Join(parts[1:], "")
+		for _, p := range hardWrap(rest, w-Width(hang)) {
+			b.WriteString("\n")
+			b.WriteString(hang)
+			b.WriteString(p)
+			n = Width(hang) + Width(p)
+		}
+		start = -1
+	}
+
+	return b.String()
+}
+
+// TermWidth returns the width of the terminal f is connected to or 0 if it is
+// not a terminal.
+func TermWidth(f *os.File) int {
+	w, _, err := term.GetSize(int(f.Fd()))
+	if err != nil || w < 1 {
+		return 0
+	}
+	return w
+}
+
+var width int
+
+// SetWidth sets the width at which the templates wrap text, 0 disables
+// wrapping.
+func SetWidth(w int) { width = w }
+
+// Table renders rows with aligned columns.
+type Table [][]string
+
+// Write writes the table with each row prefixed by indent.
+// If w > 0 the last column is wrapped to fit in w cells.
+func (t Table) Write(out io.Writer, indent string, w int) error {
+	var widths []int
+	for _, row := range t {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := Width(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range t {
+		b.Reset()
+		b.WriteString(indent)
+		for i, cell := range row {
+			if i == len(row)-1 {
+				col := Width(b.String())
+				if w > 0 && col*2 <= w {
+					pad := strings.Repeat(" ", col)
+					cell = strings.TrimPrefix(Wrap(pad+cell, w, ""), pad)
+				}
+				b.WriteString(cell)
+				break
+			}
+			b.WriteString(cell)
+			b.WriteString(strings.Repeat(" ", widths[i]-Width(cell)+2))
+		}
+		if _, err := io.WriteString(out, strings.TrimRight(b.String(), " ")+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
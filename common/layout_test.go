@@ -0,0 +1,55 @@
+package common
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := map[string]int{
+		"":                       0,
+		"стол":                   4,
+		"сто́л":                  4,
+		"\033[32mсто\033[1;33mл": 4,
+		"日本":                     4,
+	}
+	for in, exp := range tests {
+		if w := Width(in); w != exp {
+			t.Errorf("%q: expected %d got %d", in, exp, w)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in, indent, exp string
+		w               int
+	}{
+		{"  a b c", "", "  a b c", 0},
+		{"  a b c", "", "  a b c", 7},
+		{"  aaa bbb ccc", "  ", "  aaa\n    bbb\n    ccc", 8},
+		{"  при́мер сло́ва", "", "  при́мер\n  сло́ва", 10},
+		{"abcdefgh ij", "", "abcd\nefgh\nij", 4},
+		{"a\n  bb cc", "", "a\n  bb\n  cc", 5},
+	}
+	for _, test := range tests {
+		if s := Wrap(test.in, test.w, test.indent); s != test.exp {
+			t.Errorf("%q at %d: expected %q got %q", test.in, test.w, test.exp, s)
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	tbl := Table{
+		{"nom", "сто́л", "столы́"},
+		{"inst", "столо́м", "стола́ми и ещё раз и ещё"},
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := tbl.Write(buf, "  ", 34); err != nil {
+		t.Fatal(err)
+	}
+	exp := "  nom   сто́л    столы́\n  inst  столо́м  стола́ми и ещё раз\n                и ещё\n"
+	if buf.String() != exp {
+		t.Errorf("expected\n%s\ngot\n%s", exp, buf.String())
+	}
+}
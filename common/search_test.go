@@ -12,6 +12,7 @@ var d *dict.Dict
 var words openrussian.Words
 var RuQ = "драствуте"
 var EnQ = "thnk you"
+var DeQ = "dnke"
 
 func init() {
 	var err error
@@ -20,45 +21,58 @@ func init() {
 		panic(err)
 	}
 	d.InitRussianFuzzIndex()
-	d.InitEnglishFuzzIndex()
+	d.InitTranslationFuzzIndex("en")
+	d.InitTranslationFuzzIndex("de")
 	words = d.Words()
 }
 
 func TestRuQuery(t *testing.T) {
-	res := d.SearchRussianFuzzy(RuQ, true, 10)
+	res := d.SearchRussianFuzzy("en", RuQ, true, 10)
 	if len(res) == 0 || res[0].Word != "здравствуйте" {
 		t.Errorf("could not find correct word")
 	}
 }
 
 func TestEnQuery(t *testing.T) {
-	res := d.SearchEnglishFuzzy(EnQ, 10)
+	res := d.SearchTranslationFuzzy("en", EnQ, 10)
 	if len(res) == 0 || res[0].Word != "спасибо" {
 		t.Errorf("could not find correct word")
 	}
 }
 
+func TestDeQuery(t *testing.T) {
+	res := d.SearchTranslationFuzzy("de", DeQ, 10)
+	if len(res) == 0 || res[0].Word != "спасибо" {
+		t.Errorf("could not find correct word")
+	}
+	for _, tr := range res[0].Translations {
+		if tr.Lang != "de" {
+			t.Errorf("result contains translation in %s", tr.Lang)
+		}
+	}
+}
+
 func BenchmarkRuSearch(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		d.Search(RuQ, true, 100)
+		d.Search("en", RuQ, true, 100)
 	}
 }
 
 func BenchmarkEnSearch(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		d.Search(EnQ, true, 100)
+		d.Search("en", EnQ, true, 100)
 	}
 }
 
 func BenchmarkRuSearchFuzzy(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		d.SearchFuzzy(RuQ, true, 100)
+		d.SearchFuzzy("en", RuQ, true, 100)
 	}
 }
 
 func BenchmarkEnSearchFuzzy(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		d.SearchFuzzy(EnQ, true, 100)
+		d.SearchFuzzy("en", EnQ, true, 100)
 	}
 }
 
@@ -0,0 +1,228 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Style is a list of SGR parameters, e.g.: {1, 33} for bold yellow.
+type Style []int
+
+var styleNames = map[string]int{
+	"bold":      1,
+	"dim":       2,
+	"italic":    3,
+	"underline": 4,
+	"reverse":   7,
+	"black":     30,
+	"red":       31,
+	"green":     32,
+	"yellow":    33,
+	"blue":      34,
+	"magenta":   35,
+	"cyan":      36,
+	"white":     37,
+	"gray":      90,
+}
+
+// ParseStyle parses a space separated list of attributes and colors:
+//
+//	bold, dim, italic, underline, reverse
+//	black, red, green, yellow, blue, magenta, cyan, white, gray
+//	0-255: a 256-color palette index
+//	#rrggbb: a truecolor
+//
+// Colors prefixed with bg: set the background, e.g.: "bold #ff8800 bg:236".
+func ParseStyle(s string) (Style, error) {
+	style := make(Style, 0, 2)
+	for _, f := range strings.Fields(strings.ToLower(s)) {
+		bg := strings.HasPrefix(f, "bg:")
+		if bg {
+			f = f[3:]
+		}
+
+		if n, ok := styleNames[f]; ok {
+			if bg {
+				if n < 30 {
+					return nil, fmt.Errorf("invalid background '%s'", f)
+				}
+				n += 10
+			}
+			style = append(style, n)
+			continue
+		}
+
+		base := 38
+		if bg {
+			base = 48
+		}
+
+		if strings.HasPrefix(f, "#") && len(f) == 7 {
+			rgb, err := strconv.ParseUint(f[1:], 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid color '%s'", f)
+			}
+			style = append(style, base, 2, int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff))
+			continue
+		}
+
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || n > 255 {
+			return nil, fmt.Errorf("invalid style '%s'", f)
+		}
+		style = append(style, base, 5, n)
+	}
+
+	return style, nil
+}
+
+func (s *Style) UnmarshalJSON(d []byte) error {
+	var str string
+	if err := json.Unmarshal(d, &str); err != nil {
+		return err
+	}
+	style, err := ParseStyle(str)
+	*s = style
+	return err
+}
+
+func (s Style) sgr() string {
+	if len(s) == 0 {
+		return ""
+	}
+	l := make([]string, len(s))
+	for i, v := range s {
+		l[i] = strconv.Itoa(v)
+	}
+	return "\033[" + strings.Join(l, ";") + "m"
+}
+
+// Wrap surrounds str with the escape sequences for s.
+func (s Style) Wrap(str string) string {
+	if len(s) == 0 {
+		return str
+	}
+	return s.sgr() + str + "\033[0m"
+}
+
+// Theme maps the colors used in the templates (red, green, yellow, blue,
+// magenta, cyan, gray) and the stressed vowel (stress) to a Style.
+type Theme map[string]Style
+
+// Get returns the style for name, falling back to the default theme.
+func (t Theme) Get(name string) Style {
+	if s, ok := t[name]; ok {
+		return s
+	}
+	return DefaultTheme[name]
+}
+
+var DefaultTheme = Theme{
+	"red":     {31},
+	"green":   {32},
+	"yellow":  {33},
+	"blue":    {34},
+	"magenta": {35},
+	"cyan":    {36},
+	"gray":    {37},
+	"stress":  {33},
+}
+
+// Themes are the builtin themes.
+var Themes = map[string]Theme{
+	"default": DefaultTheme,
+	"bold": {
+		"stress": {1, 4, 33},
+	},
+	"256": {
+		"red":    {38, 5, 167},
+		"green":  {38, 5, 108},
+		"yellow": {38, 5, 179},
+		"blue":   {38, 5, 110},
+		"gray":   {38, 5, 250},
+		"stress": {1, 38, 5, 214},
+	},
+	"truecolor": {
+		"red":    {38, 2, 224, 108, 117},
+		"green":  {38, 2, 152, 195, 121},
+		"yellow": {38, 2, 229, 192, 123},
+		"blue":   {38, 2, 97, 175, 239},
+		"gray":   {38, 2, 171, 178, 191},
+		"stress": {1, 4, 38, 2, 255, 158, 59},
+	},
+}
+
+// LoadThemes reads a json file of themes, e.g.:
+//
+//	{"mine": {"green": "#98c379", "stress": "bold underline 214"}}
+//
+// and adds them to Themes. A missing file is not an error.
+func LoadThemes(path string) error {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	themes := make(map[string]Theme)
+	if err := json.Unmarshal(d, &themes); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for name, t := range themes {
+		Themes[name] = t
+	}
+	return nil
+}
+
+var theme = DefaultTheme
+
+// SetTheme sets the theme used by GetTpl.
+func SetTheme(t Theme) {
+	theme = t
+	tpl = nil
+}
+
+// CurrentTheme returns the theme used by GetTpl.
+func CurrentTheme() Theme { return theme }
+
+type ColorMode uint8
+
+const (
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+func ParseColorMode(s string) (ColorMode, error) {
+	switch s {
+	case "auto":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	}
+	return 0, fmt.Errorf("invalid color mode '%s', available: auto, always, never", s)
+}
+
+// Enabled reports whether colors should be written to f.
+// In auto mode colors are used if f is a terminal and NO_COLOR is not set.
+func (c ColorMode) Enabled(f *os.File) bool {
+	switch c {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
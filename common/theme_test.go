@@ -0,0 +1,42 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStyle(t *testing.T) {
+	tests := map[string]Style{
+		"":                     {},
+		"bold yellow":          {1, 33},
+		"Underline 214":        {4, 38, 5, 214},
+		"#ff8800 bg:236":       {38, 2, 255, 136, 0, 48, 5, 236},
+		"bg:red bg:#000001":    {41, 48, 2, 0, 0, 1},
+		"reverse gray bg:blue": {7, 90, 44},
+	}
+	for in, exp := range tests {
+		s, err := ParseStyle(in)
+		if err != nil {
+			t.Errorf("%q: %s", in, err)
+			continue
+		}
+		if !reflect.DeepEqual(s, exp) {
+			t.Errorf("%q: expected %v got %v", in, exp, s)
+		}
+	}
+
+	for _, in := range []string{"wat", "256", "#ff88", "bg:bold", "-1"} {
+		if _, err := ParseStyle(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestClrPop(t *testing.T) {
+	var c clrs
+	s := c.Get(32).String() + "a" + c.Get(1, 33).String() + "b" + c.Pop().String() + "c" + c.Pop().String()
+	exp := "\033[32ma\033[1;33mb\033[0m\033[32mc\033[0m"
+	if s != exp {
+		t.Errorf("expected %q got %q", exp, s)
+	}
+}
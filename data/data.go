@@ -6,3 +6,6 @@ import _ "embed"
 
 //go:embed data/db.gob
 var Words []byte
+
+// Detailed reports whether Words includes declensions and conjugations.
+const Detailed = false
@@ -4,12 +4,6 @@ package data
 
 import _ "embed"
 
-//go:embed data/LobsterRegular-R7AM.otf
-var FontLobster []byte
-
-//go:embed data/open-sans.regular.ttf
-var FontOpenSans []byte
-
 //go:embed data/app.js
 var AppJS string
 
@@ -30,3 +24,6 @@ var ImgFav []byte
 
 //go:embed data/db.web.gob
 var Words []byte
+
+// Detailed reports whether Words includes declensions and conjugations.
+const Detailed = true
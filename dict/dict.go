@@ -1,29 +1,55 @@
 package dict
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/frizinak/goru/fuzzy"
+	"github.com/frizinak/goru/morph"
 	"github.com/frizinak/goru/openrussian"
 )
 
 type fuzz struct {
-	l       sync.Mutex
+	once    sync.Once
 	words   []*openrussian.Word
 	matches []string
 	index   *fuzzy.Index
 }
 
 type Dict struct {
-	w openrussian.Words
+	w     openrussian.Words
+	langs []string
 
 	rfuzz fuzz
-	efuzz fuzz
+
+	tl    sync.Mutex
+	tfuzz map[string]*fuzz
+
+	forms   forms
+	derived derived
+
+	segOnce sync.Once
+	seg     *morph.Segmenter
 }
 
 func New(w openrussian.Words) *Dict {
+	langs := make(map[string]struct{}, 2)
+	for _, word := range w {
+		for _, t := range word.Translations {
+			langs[t.Lang] = struct{}{}
+		}
+	}
+
+	l := make([]string, 0, len(langs))
+	for lang := range langs {
+		l = append(l, lang)
+	}
+	sort.Strings(l)
+
 	return &Dict{
-		w: w,
+		w:     w,
+		langs: l,
+		tfuzz: make(map[string]*fuzz, len(l)),
 	}
 }
 
@@ -43,3 +69,15 @@ func derivedList(w *openrussian.Word, list *[]*openrussian.Word) {
 }
 
 func (d *Dict) Words() openrussian.Words { return d.w }
+
+// Languages returns the sorted list of translation languages.
+func (d *Dict) Languages() []string { return d.langs }
+
+func (d *Dict) HasLanguage(lang string) bool {
+	for _, l := range d.langs {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
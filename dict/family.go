@@ -0,0 +1,107 @@
+package dict
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+type derived struct {
+	once  sync.Once
+	index map[openrussian.ID][]*openrussian.Word
+}
+
+// Family is a word and the tree of words derived from it.
+type Family struct {
+	Word    *openrussian.Word
+	Derived []*Family
+}
+
+// Walk calls cb for f and all its descendants, depth first. depth is 0 for f.
+func (f *Family) Walk(cb func(f *Family, depth int)) {
+	f.walk(cb, 0)
+}
+
+func (f *Family) walk(cb func(f *Family, depth int), depth int) {
+	cb(f, depth)
+	for _, d := range f.Derived {
+		d.walk(cb, depth+1)
+	}
+}
+
+// Size returns the amount of words in the family.
+func (f *Family) Size() int {
+	n := 0
+	f.Walk(func(*Family, int) { n++ })
+	return n
+}
+
+// InitDerivedIndex builds the reverse DerivedFrom index used by Derived and
+// WordFamily.
+func (d *Dict) InitDerivedIndex() {
+	d.derived.once.Do(d.initDerivedIndex)
+}
+
+func (d *Dict) initDerivedIndex() {
+	index := make(map[openrussian.ID][]*openrussian.Word)
+	for _, w := range d.w {
+		if w.DerivedFrom != nil {
+			index[w.DerivedFrom.ID] = append(index[w.DerivedFrom.ID], w)
+		}
+	}
+	for _, l := range index {
+		sortFamily(l)
+	}
+	d.derived.index = index
+}
+
+// sortFamily sorts words by rank (unranked last) and then alphabetically.
+func sortFamily(l []*openrussian.Word) {
+	sort.Slice(l, func(i, j int) bool {
+		ri, rj := l[i].Rank, l[j].Rank
+		if ri != rj && (ri == 0 || rj == 0) {
+			return rj == 0
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		if l[i].Word != l[j].Word {
+			return l[i].Word < l[j].Word
+		}
+		return l[i].ID < l[j].ID
+	})
+}
+
+// Derived returns the words directly derived from w.
+func (d *Dict) Derived(w *openrussian.Word) []*openrussian.Word {
+	d.InitDerivedIndex()
+	return d.derived.index[w.ID]
+}
+
+// Root returns the word at the end of the DerivedFrom chain of w.
+func Root(w *openrussian.Word) *openrussian.Word {
+	if l := DerivedList(w); len(l) != 0 {
+		return l[len(l)-1]
+	}
+	return w
+}
+
+// WordFamily returns the tree of words derived from the root of w, which
+// includes w itself.
+func (d *Dict) WordFamily(w *openrussian.Word) *Family {
+	seen := make(map[openrussian.ID]struct{})
+	return d.family(Root(w), seen)
+}
+
+func (d *Dict) family(w *openrussian.Word, seen map[openrussian.ID]struct{}) *Family {
+	seen[w.ID] = struct{}{}
+	f := &Family{Word: w}
+	for _, c := range d.Derived(w) {
+		if _, ok := seen[c.ID]; ok {
+			continue
+		}
+		f.Derived = append(f.Derived, d.family(c, seen))
+	}
+	return f
+}
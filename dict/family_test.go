@@ -0,0 +1,40 @@
+package dict
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+func TestWordFamily(t *testing.T) {
+	words := make(openrussian.Words)
+	add := func(id openrussian.ID, word string, rank uint64, from openrussian.ID) *openrussian.Word {
+		w := &openrussian.Word{ID: id, Word: word, Rank: rank, DerivedFrom: words[from]}
+		words[id] = w
+		return w
+	}
+	add(1, "писать", 10, 0)
+	add(2, "писатель", 0, 1)
+	add(3, "написать", 20, 1)
+	add(4, "переписать", 5, 1)
+	add(5, "переписка", 0, 4)
+	add(6, "стол", 1, 0)
+
+	d := New(words)
+	var s []string
+	d.WordFamily(words[5]).Walk(func(f *Family, depth int) {
+		s = append(s, strings.Repeat(" ", depth)+f.Word.Word)
+	})
+	exp := "писать\n переписать\n  переписка\n написать\n писатель"
+	if got := strings.Join(s, "\n"); got != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, got)
+	}
+
+	if n := d.WordFamily(words[6]).Size(); n != 1 {
+		t.Errorf("expected a family of 1, got %d", n)
+	}
+	if l := d.Derived(words[3]); len(l) != 0 {
+		t.Errorf("expected no derived words, got %d", len(l))
+	}
+}
@@ -0,0 +1,126 @@
+package dict
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+// Filter selects words by level, type, rank or an explicit list.
+// Zero values match everything.
+type Filter struct {
+	Levels  []openrussian.LanguageLevel
+	Types   []openrussian.WordType
+	MinRank uint64
+	MaxRank uint64
+	Words   []string
+}
+
+func split(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// ParseLevels parses a comma separated list of levels, e.g.: A1,A2.
+func ParseLevels(s string) ([]openrussian.LanguageLevel, error) {
+	var l []openrussian.LanguageLevel
+	for _, v := range split(s) {
+		level, ok := openrussian.ParseLanguageLevel(v)
+		if !ok {
+			return nil, fmt.Errorf("invalid level '%s'", v)
+		}
+		l = append(l, level)
+	}
+	return l, nil
+}
+
+// ParseTypes parses a comma separated list of word types, e.g.: noun,verb.
+func ParseTypes(s string) ([]openrussian.WordType, error) {
+	var l []openrussian.WordType
+	for _, v := range split(s) {
+		t, ok := openrussian.ParseWordType(v)
+		if !ok {
+			return nil, fmt.Errorf("invalid word type '%s'", v)
+		}
+		l = append(l, t)
+	}
+	return l, nil
+}
+
+func (f Filter) wordSet() map[string]struct{} {
+	if len(f.Words) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(f.Words))
+	for _, word := range f.Words {
+		word = strings.ToLower(strings.TrimSpace(openrussian.Stressed(word).Unstressed()))
+		set[word] = struct{}{}
+	}
+	return set
+}
+
+func (f Filter) Match(w *openrussian.Word) bool {
+	return f.match(w, f.wordSet())
+}
+
+func (f Filter) match(w *openrussian.Word, set map[string]struct{}) bool {
+	if len(f.Levels) != 0 {
+		found := false
+		for _, l := range f.Levels {
+			if w.LanguageLevel == l {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.Types) != 0 {
+		found := false
+		for _, t := range f.Types {
+			if w.WordType == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.MinRank != 0 || f.MaxRank != 0 {
+		if w.Rank == 0 || w.Rank < f.MinRank {
+			return false
+		}
+		if f.MaxRank != 0 && w.Rank > f.MaxRank {
+			return false
+		}
+	}
+
+	if set != nil {
+		if _, ok := set[w.Lower]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f Filter) Filter(words openrussian.Words) openrussian.Words {
+	set := f.wordSet()
+	n := make(openrussian.Words)
+	for id, w := range words {
+		if f.match(w, set) {
+			n[id] = w
+		}
+	}
+	return n
+}
@@ -0,0 +1,66 @@
+package dict
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+// Form is an inflected form (or the lemma itself) of a word.
+type Form struct {
+	Word     *openrussian.Word
+	Stressed openrussian.Stressed
+}
+
+type forms struct {
+	once  sync.Once
+	index map[string][]Form
+}
+
+// FormKey normalizes s for form lookups: lowercase, without stress marks and
+// with ё replaced by е as most texts don't write it.
+func FormKey(s string) string {
+	s = strings.ToLower(openrussian.Stressed(s).Unstressed())
+	return strings.ReplaceAll(s, "ё", "е")
+}
+
+// InitFormIndex builds the index used by LookupForm.
+func (d *Dict) InitFormIndex() {
+	d.forms.once.Do(d.initFormIndex)
+}
+
+func (d *Dict) initFormIndex() {
+	index := make(map[string][]Form, len(d.w)*4)
+	for _, w := range d.w {
+		for _, f := range w.Forms() {
+			if strings.Contains(string(f), " ") {
+				continue
+			}
+			k := FormKey(string(f))
+			index[k] = append(index[k], Form{Word: w, Stressed: f})
+		}
+	}
+	d.forms.index = index
+}
+
+// LookupForm returns every word that has form as one of its forms.
+func (d *Dict) LookupForm(form string) []Form {
+	d.InitFormIndex()
+	return d.forms.index[FormKey(form)]
+}
+
+// Lemmas returns the distinct words that have form as one of their forms.
+func (d *Dict) Lemmas(form string) []*openrussian.Word {
+	f := d.LookupForm(form)
+	seen := make(map[openrussian.ID]struct{}, len(f))
+	words := make([]*openrussian.Word, 0, len(f))
+	for _, f := range f {
+		if _, ok := seen[f.Word.ID]; ok {
+			continue
+		}
+		seen[f.Word.ID] = struct{}{}
+		words = append(words, f.Word)
+	}
+	return words
+}
@@ -8,16 +8,13 @@ import (
 	"github.com/frizinak/goru/openrussian"
 )
 
+// InitRussianFuzzIndex builds the index used by the russian fuzzy search.
+// It is safe to call concurrently, e.g.: in the background while searching.
 func (d *Dict) InitRussianFuzzIndex() {
-	if d.rfuzz.index != nil {
-		return
-	}
-	d.rfuzz.l.Lock()
-	if d.rfuzz.index != nil {
-		d.rfuzz.l.Unlock()
-		return
-	}
+	d.rfuzz.once.Do(d.initRussianFuzzIndex)
+}
 
+func (d *Dict) initRussianFuzzIndex() {
 	words := make([]*openrussian.Word, 0, len(d.w))
 	l := make([]string, 0, len(d.w))
 	for _, w := range d.w {
@@ -26,24 +23,35 @@ func (d *Dict) InitRussianFuzzIndex() {
 	}
 	d.rfuzz.words = words
 	d.rfuzz.index = fuzzy.NewIndex(2, l)
-	d.rfuzz.l.Unlock()
 }
 
-func (d *Dict) InitEnglishFuzzIndex() {
-	if d.efuzz.index != nil {
-		return
-	}
-	d.efuzz.l.Lock()
-	if d.efuzz.index != nil {
-		d.efuzz.l.Unlock()
-		return
+func (d *Dict) translationFuzz(lang string) *fuzz {
+	d.tl.Lock()
+	f, ok := d.tfuzz[lang]
+	if !ok {
+		f = &fuzz{}
+		d.tfuzz[lang] = f
 	}
+	d.tl.Unlock()
+	return f
+}
+
+// InitTranslationFuzzIndex builds the index used by the fuzzy search for
+// translations in lang. It is safe to call concurrently.
+func (d *Dict) InitTranslationFuzzIndex(lang string) {
+	f := d.translationFuzz(lang)
+	f.once.Do(func() { d.initTranslationFuzzIndex(f, lang) })
+}
 
+func (d *Dict) initTranslationFuzzIndex(f *fuzz, lang string) {
 	words := make([]*openrussian.Word, 0, len(d.w))
 	matches := make([]string, 0, len(d.w))
 	l := make([]string, 0, len(d.w))
 	for _, w := range d.w {
 		for _, t := range w.Translations {
+			if t.Lang != lang {
+				continue
+			}
 			for _, kw := range t.Words() {
 				words = append(words, w)
 				matches = append(matches, kw)
@@ -51,10 +59,9 @@ func (d *Dict) InitEnglishFuzzIndex() {
 			}
 		}
 	}
-	d.efuzz.words = words
-	d.efuzz.matches = matches
-	d.efuzz.index = fuzzy.NewIndex(2, l)
-	d.efuzz.l.Unlock()
+	f.words = words
+	f.matches = matches
+	f.index = fuzzy.NewIndex(2, l)
 }
 
 func (d *Dict) GetRussianFuzz() *fuzzy.Index {
@@ -62,15 +69,20 @@ func (d *Dict) GetRussianFuzz() *fuzzy.Index {
 	return d.rfuzz.index
 }
 
-func (d *Dict) GetEnglishFuzz() *fuzzy.Index {
-	d.InitEnglishFuzzIndex()
-	return d.efuzz.index
+func (d *Dict) GetTranslationFuzz(lang string) *fuzzy.Index {
+	d.InitTranslationFuzzIndex(lang)
+	return d.translationFuzz(lang).index
 }
 
 const levenshteinMax = 500
 
-func (d *Dict) SearchEnglishFuzzy(qry string, max int) []*openrussian.Word {
-	d.InitEnglishFuzzIndex()
+func (d *Dict) SearchTranslationFuzzy(lang, qry string, max int) []*openrussian.Word {
+	return d.searchTranslationFuzzy(lang, qry, max).Words()
+}
+
+func (d *Dict) searchTranslationFuzzy(lang, qry string, max int) Results {
+	d.InitTranslationFuzzIndex(lang)
+	f := d.translationFuzz(lang)
 	if len(qry) > 1<<8-1 {
 		qry = qry[:1<<8-1]
 	}
@@ -80,13 +92,13 @@ func (d *Dict) SearchEnglishFuzzy(qry string, max int) []*openrussian.Word {
 	}
 
 	tmp := make(Results, 0, max)
-	d.efuzz.index.Search(strings.ToLower(qry), func(index int, score, low, high uint8) {
+	f.index.Search(strings.ToLower(qry), func(index int, score, low, high uint8) {
 		if score >= lq {
 			tmp = append(
 				tmp,
 				&Result{
-					Word:  d.efuzz.words[index],
-					Match: d.efuzz.matches[index],
+					Word:  f.words[index],
+					Match: f.matches[index],
 					Score: int(score),
 				},
 			)
@@ -116,10 +128,14 @@ func (d *Dict) SearchEnglishFuzzy(qry string, max int) []*openrussian.Word {
 	}
 
 	sort.Sort(results)
-	return results2words(results, max)
+	return limit(results, lang, max)
+}
+
+func (d *Dict) SearchRussianFuzzy(lang, qry string, includeWithoutTranslation bool, max int) []*openrussian.Word {
+	return d.searchRussianFuzzy(lang, qry, includeWithoutTranslation, max).Words()
 }
 
-func (d *Dict) SearchRussianFuzzy(qry string, includeWithoutTranslation bool, max int) []*openrussian.Word {
+func (d *Dict) searchRussianFuzzy(lang, qry string, includeWithoutTranslation bool, max int) Results {
 	d.InitRussianFuzzIndex()
 	if len(qry) > 1<<8-1 {
 		qry = qry[:1<<8-1]
@@ -143,7 +159,7 @@ func (d *Dict) SearchRussianFuzzy(qry string, includeWithoutTranslation bool, ma
 
 	results := make(Results, 0, len(tmp))
 	for _, w := range tmp {
-		if !includeWithoutTranslation && len(w.Translations) == 0 {
+		if !includeWithoutTranslation && !hasTranslation(w.Word, lang) {
 			continue
 		}
 		w.Levenshtein(qry)
@@ -151,5 +167,5 @@ func (d *Dict) SearchRussianFuzzy(qry string, includeWithoutTranslation bool, ma
 	}
 
 	sort.Sort(results)
-	return results2words(results, max)
+	return limit(results, lang, max)
 }
@@ -0,0 +1,29 @@
+package dict
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+func TestFuzzIndexConcurrent(t *testing.T) {
+	words := openrussian.Words{
+		1: {ID: 1, Word: "стол", Lower: "стол", Translations: []*openrussian.Translation{{Lang: "en", Translation: "table"}}},
+		2: {ID: 2, Word: "стул", Lower: "стул", Translations: []*openrussian.Translation{{Lang: "en", Translation: "chair"}}},
+	}
+	d := New(words)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { d.InitRussianFuzzIndex(); wg.Done() }()
+	go func() { d.InitTranslationFuzzIndex("en"); wg.Done() }()
+
+	if res := d.SearchRussianFuzzy("en", "стл", true, 10); len(res) == 0 {
+		t.Error("expected russian results")
+	}
+	if res := d.SearchTranslationFuzzy("en", "tabel", 10); len(res) == 0 || res[0].ID != 1 {
+		t.Errorf("expected стол, got %v", res)
+	}
+	wg.Wait()
+}
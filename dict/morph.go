@@ -0,0 +1,22 @@
+package dict
+
+import (
+	"github.com/frizinak/goru/morph"
+	"github.com/frizinak/goru/openrussian"
+)
+
+// Segmenter returns the morph.Segmenter for the words in d.
+func (d *Dict) Segmenter() *morph.Segmenter {
+	d.segOnce.Do(func() { d.seg = morph.New(d.w) })
+	return d.seg
+}
+
+// Segment splits w into its morphemes, see morph.Segmenter.
+func (d *Dict) Segment(w *openrussian.Word) morph.Segmentation {
+	return d.Segmenter().Segment(w)
+}
+
+// WithRoot returns the words with the given root.
+func (d *Dict) WithRoot(root string) []*openrussian.Word {
+	return d.Segmenter().WithRoot(root)
+}
@@ -39,7 +39,21 @@ func (r *Result) Levenshtein(qry string) {
 	r.Score = inverseScore - Levenshtein([]rune(r.Word.Word), []rune(qry))
 }
 
-func results2words(r []*Result, max int) []*openrussian.Word {
+// Distance is how far the match is from the query, 0 being the best.
+// For exact translation searches it is the position of the matching meaning,
+// otherwise the levenshtein distance.
+func (r *Result) Distance() int { return inverseScore - r.Score }
+
+func (r Results) Words() []*openrussian.Word {
+	w := make([]*openrussian.Word, len(r))
+	for i := range r {
+		w[i] = r[i].Word
+	}
+	return w
+}
+
+// limit returns at most max results with localized words.
+func limit(r Results, lang string, max int) Results {
 	if max == 0 {
 		max = 1000
 	}
@@ -47,48 +61,77 @@ func results2words(r []*Result, max int) []*openrussian.Word {
 		max = len(r)
 	}
 	r = r[:max]
-	w := make([]*openrussian.Word, len(r))
+	n := make(Results, len(r))
 	for i, r := range r {
-		w[i] = r.Word
+		n[i] = &Result{Word: r.Word.Localized(lang), Match: r.Match, Score: r.Score}
 	}
-	return w
+	return n
+}
+
+func (d *Dict) Search(lang, qry string, includeWithoutTranslation bool, max int) ([]*openrussian.Word, bool) {
+	r, cyr := d.SearchResults(lang, qry, includeWithoutTranslation, max)
+	return r.Words(), cyr
 }
 
-func (d *Dict) Search(qry string, includeWithoutTranslation bool, max int) ([]*openrussian.Word, bool) {
+func (d *Dict) SearchFuzzy(lang, qry string, includeWithoutTranslation bool, max int) ([]*openrussian.Word, bool) {
+	r, cyr := d.SearchFuzzyResults(lang, qry, includeWithoutTranslation, max)
+	return r.Words(), cyr
+}
+
+// SearchResults is Search but also returns the match scores.
+func (d *Dict) SearchResults(lang, qry string, includeWithoutTranslation bool, max int) (Results, bool) {
 	if IsCyrillic(qry) {
-		return d.SearchRussian(qry, includeWithoutTranslation, max), true
+		return d.searchRussian(lang, qry, includeWithoutTranslation, max), true
 	}
 
-	return d.SearchEnglish(qry, max), false
+	return d.searchTranslation(lang, qry, max), false
 }
 
-func (d *Dict) SearchFuzzy(qry string, includeWithoutTranslation bool, max int) ([]*openrussian.Word, bool) {
+// SearchFuzzyResults is SearchFuzzy but also returns the match scores.
+func (d *Dict) SearchFuzzyResults(lang, qry string, includeWithoutTranslation bool, max int) (Results, bool) {
 	if IsCyrillic(qry) {
-		return d.SearchRussianFuzzy(qry, includeWithoutTranslation, max), true
+		return d.searchRussianFuzzy(lang, qry, includeWithoutTranslation, max), true
 	}
 
-	return d.SearchEnglishFuzzy(qry, max), false
+	return d.searchTranslationFuzzy(lang, qry, max), false
 }
 
-func (d *Dict) SearchEnglish(qry string, max int) []*openrussian.Word {
+func (d *Dict) SearchTranslation(lang, qry string, max int) []*openrussian.Word {
+	return d.searchTranslation(lang, qry, max).Words()
+}
+
+func (d *Dict) searchTranslation(lang, qry string, max int) Results {
 	qry = strings.ToLower(qry)
 	results := make(Results, 0)
 	for _, w := range d.w {
-		if found, ix := w.HasTranslation(qry); found {
+		if found, ix := w.HasTranslation(lang, qry); found {
 			results = append(results, &Result{Word: w, Score: inverseScore - ix})
 		}
 	}
 
 	sort.Sort(results)
-	return results2words(results, max)
+	return limit(results, lang, max)
+}
+
+func hasTranslation(w *openrussian.Word, lang string) bool {
+	for _, t := range w.Translations {
+		if t.Lang == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dict) SearchRussian(lang, qry string, includeWithoutTranslation bool, max int) []*openrussian.Word {
+	return d.searchRussian(lang, qry, includeWithoutTranslation, max).Words()
 }
 
-func (d *Dict) SearchRussian(qry string, includeWithoutTranslation bool, max int) []*openrussian.Word {
+func (d *Dict) searchRussian(lang, qry string, includeWithoutTranslation bool, max int) Results {
 	results := make(Results, 0)
 
 	qryLow := strings.ToLower(qry)
 	for _, w := range d.w {
-		if !includeWithoutTranslation && len(w.Translations) == 0 {
+		if !includeWithoutTranslation && !hasTranslation(w, lang) {
 			continue
 		}
 		if strings.Contains(w.Lower, qryLow) {
@@ -99,7 +142,7 @@ func (d *Dict) SearchRussian(qry string, includeWithoutTranslation bool, max int
 	}
 
 	sort.Sort(results)
-	return results2words(results, max)
+	return limit(results, lang, max)
 }
 
 func IsCyrillic(qry string) bool {
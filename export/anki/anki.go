@@ -0,0 +1,128 @@
+package anki
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image/color"
+	"image/png"
+
+	"github.com/frizinak/goru/export"
+	"github.com/frizinak/goru/image"
+	"github.com/frizinak/goru/openrussian"
+)
+
+var (
+	imgFG = color.NRGBA{0, 0, 0, 255}
+	imgBG = color.NRGBA{255, 255, 255, 255}
+)
+
+// Fields are the note fields in the order they are exported.
+var Fields = []string{
+	"Russian",
+	"Translation",
+	"Gender",
+	"Example",
+	"ExampleTranslation",
+	"Image",
+}
+
+type Card struct {
+	ID                 openrussian.ID
+	Russian            string
+	Translation        string
+	Gender             string
+	Example            string
+	ExampleTranslation string
+
+	ImageName string
+	Image     []byte
+}
+
+// fields returns the html note fields in the order of Fields.
+func (c *Card) fields() []string {
+	img := ""
+	if c.ImageName != "" {
+		img = fmt.Sprintf(`<img src="%s">`, html.EscapeString(c.ImageName))
+	}
+	return []string{
+		html.EscapeString(c.Russian),
+		html.EscapeString(c.Translation),
+		html.EscapeString(c.Gender),
+		html.EscapeString(c.Example),
+		html.EscapeString(c.ExampleTranslation),
+		img,
+	}
+}
+
+// NewCard creates a card for w using its translations in the given language
+// and renders the cursive image if withImage is set.
+func NewCard(w *openrussian.Word, lang string, withImage bool) (*Card, error) {
+	c := &Card{
+		ID:      w.ID,
+		Russian: w.Stressed.String(),
+	}
+
+	if w.NounInfo != nil {
+		c.Gender = w.NounInfo.Gender.String()
+	}
+
+	trans := w.TranslationsFor(lang)
+	for i, t := range trans {
+		if i != 0 {
+			c.Translation += "; "
+		}
+		c.Translation += t.Translation
+		if c.Example == "" && t.Example != "" {
+			c.Example = openrussian.Stressed(t.Example).String()
+			c.ExampleTranslation = t.ExampleTranslation
+		}
+	}
+
+	if c.Example == "" {
+		for _, s := range w.Sentences() {
+			if t := s.TranslationsFor(lang); len(t) != 0 {
+				c.Example = s.Russian.String()
+				c.ExampleTranslation = t[0].Translation
+				break
+			}
+		}
+	}
+
+	if !withImage {
+		return c, nil
+	}
+
+	str := w.Stressed.Parse().String()
+	img, err := image.Image(150, str, str, true, imgFG, imgBG)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+	c.ImageName = fmt.Sprintf("goru-%d.png", w.ID)
+	c.Image = buf.Bytes()
+
+	return c, nil
+}
+
+// NewCards creates a card for every word that has a translation in lang,
+// ordered by rank.
+func NewCards(words openrussian.Words, lang string, withImage bool) ([]*Card, error) {
+	sorted := export.SortedWords(words)
+	export.SortByRank(sorted)
+	cards := make([]*Card, 0, len(sorted))
+	for _, w := range sorted {
+		if len(w.TranslationsFor(lang)) == 0 {
+			continue
+		}
+		c, err := NewCard(w, lang, withImage)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
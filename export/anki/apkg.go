@@ -0,0 +1,353 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// schema is the anki 2.1 legacy (v11) collection schema, which every anki
+// version can import.
+const schema = `
+CREATE TABLE col (
+    id integer PRIMARY KEY, crt integer NOT NULL, mod integer NOT NULL,
+    scm integer NOT NULL, ver integer NOT NULL, dty integer NOT NULL,
+    usn integer NOT NULL, ls integer NOT NULL, conf text NOT NULL,
+    models text NOT NULL, decks text NOT NULL, dconf text NOT NULL,
+    tags text NOT NULL
+);
+CREATE TABLE notes (
+    id integer PRIMARY KEY, guid text NOT NULL, mid integer NOT NULL,
+    mod integer NOT NULL, usn integer NOT NULL, tags text NOT NULL,
+    flds text NOT NULL, sfld integer NOT NULL, csum integer NOT NULL,
+    flags integer NOT NULL, data text NOT NULL
+);
+CREATE TABLE cards (
+    id integer PRIMARY KEY, nid integer NOT NULL, did integer NOT NULL,
+    ord integer NOT NULL, mod integer NOT NULL, usn integer NOT NULL,
+    type integer NOT NULL, queue integer NOT NULL, due integer NOT NULL,
+    ivl integer NOT NULL, factor integer NOT NULL, reps integer NOT NULL,
+    lapses integer NOT NULL, left integer NOT NULL, odue integer NOT NULL,
+    odid integer NOT NULL, flags integer NOT NULL, data text NOT NULL
+);
+CREATE TABLE revlog (
+    id integer PRIMARY KEY, cid integer NOT NULL, usn integer NOT NULL,
+    ease integer NOT NULL, ivl integer NOT NULL, lastIvl integer NOT NULL,
+    factor integer NOT NULL, time integer NOT NULL, type integer NOT NULL
+);
+CREATE TABLE graves (
+    usn integer NOT NULL, oid integer NOT NULL, type integer NOT NULL
+);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+const css = `.card {
+    font-family: sans-serif;
+    font-size: 24px;
+    text-align: center;
+    color: black;
+    background-color: white;
+}
+.gender { color: #888; font-size: 16px; }
+.example { font-size: 18px; margin-top: 1em; }
+.example-tl { font-size: 16px; color: #888; }
+img { max-width: 100%; }`
+
+const front = `<div class="ru">{{Russian}}</div>
+{{#Image}}<div>{{Image}}</div>{{/Image}}`
+
+const back = `<div class="tl">{{Translation}}</div>`
+
+const detail = `
+{{#Gender}}<div class="gender">{{Gender}}</div>{{/Gender}}
+{{#Example}}<div class="example">{{Example}}</div>{{/Example}}
+{{#ExampleTranslation}}<div class="example-tl">{{ExampleTranslation}}</div>{{/ExampleTranslation}}`
+
+// id derives a stable anki id from s so re-importing an updated deck
+// updates existing notes instead of duplicating them.
+func id(s string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return int64(h.Sum64()>>12) + 1
+}
+
+var reTags = regexp.MustCompile(`<[^>]*>`)
+
+func checksum(field string) int64 {
+	s := sha1.Sum([]byte(reTags.ReplaceAllString(field, "")))
+	n, _ := strconv.ParseInt(fmt.Sprintf("%x", s[:4]), 16, 64)
+	return n
+}
+
+func model(mid, did int64, now int64) map[string]interface{} {
+	flds := make([]map[string]interface{}, len(Fields))
+	for i, f := range Fields {
+		flds[i] = map[string]interface{}{
+			"name":   f,
+			"ord":    i,
+			"sticky": false,
+			"rtl":    false,
+			"font":   "Arial",
+			"size":   20,
+			"media":  []string{},
+		}
+	}
+
+	tmpl := func(ord int, name, q, a string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":  name,
+			"ord":   ord,
+			"qfmt":  q,
+			"afmt":  a,
+			"did":   nil,
+			"bqfmt": "",
+			"bafmt": "",
+		}
+	}
+
+	return map[string]interface{}{
+		"id":    mid,
+		"name":  "goru",
+		"type":  0,
+		"mod":   now,
+		"usn":   -1,
+		"sortf": 0,
+		"did":   did,
+		"tmpls": []map[string]interface{}{
+			tmpl(0, "Recognition", front, "{{FrontSide}}<hr id=answer>"+back+detail),
+			tmpl(1, "Recall", back, "{{FrontSide}}<hr id=answer>"+front+detail),
+		},
+		"flds":      flds,
+		"css":       css,
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"tags":      []string{},
+		"vers":      []string{},
+		"req": []interface{}{
+			[]interface{}{0, "all", []int{0}},
+			[]interface{}{1, "all", []int{1}},
+		},
+	}
+}
+
+func deck(did int64, name string, now int64) map[string]interface{} {
+	return map[string]interface{}{
+		"id":               did,
+		"name":             name,
+		"desc":             "",
+		"mod":              now,
+		"usn":              -1,
+		"conf":             1,
+		"dyn":              0,
+		"collapsed":        false,
+		"extendNew":        10,
+		"extendRev":        50,
+		"newToday":         []int{0, 0},
+		"revToday":         []int{0, 0},
+		"lrnToday":         []int{0, 0},
+		"timeToday":        []int{0, 0},
+		"browserCollapsed": false,
+	}
+}
+
+const dconf = `{"1":{"id":1,"name":"Default","mod":0,"usn":0,"maxTaken":60,"autoplay":true,"timer":0,"replayq":true,"dyn":false,` +
+	`"new":{"delays":[1,10],"ints":[1,4,7],"initialFactor":2500,"order":1,"perDay":20,"bury":false},` +
+	`"rev":{"perDay":200,"ease4":1.3,"ivlFct":1,"maxIvl":36500,"bury":false,"hardFactor":1.2},` +
+	`"lapse":{"delays":[10],"mult":0,"minInt":1,"leechFails":8,"leechAction":1}}}`
+
+func jsonString(v interface{}) (string, error) {
+	d, err := json.Marshal(v)
+	return string(d), err
+}
+
+// APKG writes the cards as an anki package named deckName to path.
+func APKG(path, deckName string, cards []*Card) error {
+	tmp := fmt.Sprintf("%s.%d.tmp", path, time.Now().UnixNano())
+	err := collection(tmp, deckName, cards)
+	if err == nil {
+		err = pack(path, tmp, cards)
+	}
+	os.Remove(tmp)
+	return err
+}
+
+func collection(path, deckName string, cards []*Card) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+
+	if err := writeCollection(db, deckName, cards); err != nil {
+		db.Close()
+		return err
+	}
+
+	return db.Close()
+}
+
+func writeCollection(db *sql.DB, deckName string, cards []*Card) error {
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sec, ms := now.Unix(), now.UnixNano()/1e6
+	mid, did := id("goru-model"), id("goru-deck-"+deckName)
+
+	models, err := jsonString(map[string]interface{}{
+		strconv.FormatInt(mid, 10): model(mid, did, sec),
+	})
+	if err != nil {
+		return err
+	}
+	decks, err := jsonString(map[string]interface{}{
+		"1":                        deck(1, "Default", sec),
+		strconv.FormatInt(did, 10): deck(did, deckName, sec),
+	})
+	if err != nil {
+		return err
+	}
+	conf, err := jsonString(map[string]interface{}{
+		"activeDecks":   []int64{did},
+		"curDeck":       did,
+		"curModel":      mid,
+		"nextPos":       len(cards) + 1,
+		"sortType":      "noteFld",
+		"sortBackwards": false,
+		"newSpread":     0,
+		"collapseTime":  1200,
+		"timeLim":       0,
+		"estTimes":      true,
+		"dueCounts":     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		sec, ms, ms, conf, models, decks, dconf,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	note, err := tx.Prepare(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, '', ?, ?, ?, 0, '')`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer note.Close()
+	card, err := tx.Prepare(`INSERT INTO cards VALUES (?, ?, ?, ?, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer card.Close()
+
+	for i, c := range cards {
+		guid := fmt.Sprintf("goru%d", c.ID)
+		nid := id(guid)
+		f := c.fields()
+		_, err := note.Exec(nid, guid, mid, sec, strings.Join(f, "\x1f"), f[0], checksum(f[0]))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		for ord := 0; ord < 2; ord++ {
+			cid := id(fmt.Sprintf("%s-%d", guid, ord))
+			if _, err := card.Exec(cid, nid, did, ord, sec, i+1); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func pack(path, collection string, cards []*Card) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = writePackage(f, collection, cards)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+func writePackage(w io.Writer, collection string, cards []*Card) error {
+	z := zip.NewWriter(w)
+	add := func(name string, r io.Reader) error {
+		w, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, r)
+		return err
+	}
+
+	col, err := os.Open(collection)
+	if err != nil {
+		return err
+	}
+	err = add("collection.anki2", col)
+	col.Close()
+	if err != nil {
+		return err
+	}
+
+	media := make(map[string]string)
+	n := 0
+	for _, c := range cards {
+		if c.Image == nil {
+			continue
+		}
+		name := strconv.Itoa(n)
+		media[name] = c.ImageName
+		if err := add(name, bytes.NewReader(c.Image)); err != nil {
+			return err
+		}
+		n++
+	}
+
+	m, err := jsonString(media)
+	if err != nil {
+		return err
+	}
+	if err := add("media", strings.NewReader(m)); err != nil {
+		return err
+	}
+
+	return z.Close()
+}
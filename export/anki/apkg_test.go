@@ -0,0 +1,153 @@
+package anki
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testCards() []*Card {
+	return []*Card{
+		{
+			ID:          3,
+			Russian:     "сто́л",
+			Translation: "table",
+			Gender:      "masculine",
+			Example:     "Книга на столе́.",
+			ImageName:   "goru-3.png",
+			Image:       []byte("png 3"),
+		},
+		{
+			ID:          4,
+			Russian:     "де́лать",
+			Translation: "to do; to make",
+		},
+	}
+}
+
+func TestAPKG(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "goru.apkg")
+	if err := APKG(path, "goru", testCards()); err != nil {
+		t.Fatal(err)
+	}
+
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer z.Close()
+
+	files := make(map[string][]byte)
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = d
+	}
+
+	var media map[string]string
+	if err := json.Unmarshal(files["media"], &media); err != nil {
+		t.Fatal(err)
+	}
+	if len(media) != 1 || media["0"] != "goru-3.png" {
+		t.Errorf("unexpected media map: %v", media)
+	}
+	if string(files["0"]) != "png 3" {
+		t.Errorf("unexpected media file 0: %q", files["0"])
+	}
+
+	col := filepath.Join(dir, "collection.anki2")
+	if err := os.WriteFile(col, files["collection.anki2"], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", col)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT id, guid, flds, sfld, csum FROM notes ORDER BY guid`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	exp := []struct {
+		guid   string
+		fields []string
+	}{
+		{"goru3", []string{"сто́л", "table", "masculine", "Книга на столе́.", "", `<img src="goru-3.png">`}},
+		{"goru4", []string{"де́лать", "to do; to make", "", "", "", ""}},
+	}
+	n := 0
+	for ; rows.Next(); n++ {
+		var nid, csum int64
+		var guid, flds, sfld string
+		if err := rows.Scan(&nid, &guid, &flds, &sfld, &csum); err != nil {
+			t.Fatal(err)
+		}
+		if n >= len(exp) {
+			continue
+		}
+		e := exp[n]
+		if guid != e.guid || nid != id(e.guid) {
+			t.Errorf("note %d: expected %s (%d) got %s (%d)", n, e.guid, id(e.guid), guid, nid)
+		}
+		if got := strings.Split(flds, "\x1f"); strings.Join(got, "|") != strings.Join(e.fields, "|") {
+			t.Errorf("%s: expected fields %q got %q", guid, e.fields, got)
+		}
+		if sfld != e.fields[0] {
+			t.Errorf("%s: expected sort field %s got %s", guid, e.fields[0], sfld)
+		}
+		sum := sha1.Sum([]byte(e.fields[0]))
+		if exp := int64(binary.BigEndian.Uint32(sum[:4])); csum != exp {
+			t.Errorf("%s: expected checksum %d got %d", guid, exp, csum)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != len(exp) {
+		t.Errorf("expected %d notes got %d", len(exp), n)
+	}
+
+	var cards, decks, ords int
+	err = db.QueryRow(`SELECT COUNT(*), COUNT(DISTINCT did), COUNT(DISTINCT ord) FROM cards c JOIN notes n ON n.id = c.nid`).
+		Scan(&cards, &decks, &ords)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cards != 4 || decks != 1 || ords != 2 {
+		t.Errorf("expected 4 cards in 1 deck with 2 templates, got %d cards, %d decks and %d templates", cards, decks, ords)
+	}
+
+	var did int64
+	if err := db.QueryRow(`SELECT did FROM cards WHERE id = ?`, id("goru3-1")).Scan(&did); err != nil {
+		t.Fatal(err)
+	}
+	if did != id("goru-deck-goru") {
+		t.Errorf("expected deck %d got %d", id("goru-deck-goru"), did)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	sum := sha1.Sum([]byte("bold"))
+	exp := int64(binary.BigEndian.Uint32(sum[:4]))
+	if got := checksum("<b>bold</b>"); got != exp {
+		t.Errorf("expected %d got %d", exp, got)
+	}
+}
@@ -0,0 +1,45 @@
+package anki
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func tsvField(s string) string {
+	s = strings.ReplaceAll(s, "\t", " ")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// TSV writes the cards as an Anki importable tab separated file.
+// Images are written to mediaDir and should be copied to Anki's
+// collection.media directory.
+func TSV(w io.Writer, mediaDir string, cards []*Card) error {
+	header := fmt.Sprintf(
+		"#separator:tab\n#html:true\n#columns:%s\n",
+		strings.Join(Fields, "\t"),
+	)
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	for _, c := range cards {
+		if c.Image != nil {
+			if err := os.WriteFile(filepath.Join(mediaDir, c.ImageName), c.Image, 0644); err != nil {
+				return err
+			}
+		}
+
+		f := c.fields()
+		for i := range f {
+			f[i] = tsvField(f[i])
+		}
+		if _, err := io.WriteString(w, strings.Join(f, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package anki
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTSV(t *testing.T) {
+	dir := t.TempDir()
+	cards := testCards()
+	cards[1].Example = "a\tb\nc <d>"
+
+	buf := bytes.NewBuffer(nil)
+	if err := TSV(buf, dir, cards); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := "#separator:tab\n" +
+		"#html:true\n" +
+		"#columns:Russian\tTranslation\tGender\tExample\tExampleTranslation\tImage\n" +
+		"сто́л\ttable\tmasculine\tКнига на столе́.\t\t<img src=\"goru-3.png\">\n" +
+		"де́лать\tto do; to make\t\ta b<br>c &lt;d&gt;\t\t\n"
+	if got := buf.String(); got != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, got)
+	}
+
+	img, err := os.ReadFile(filepath.Join(dir, "goru-3.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(img) != "png 3" {
+		t.Errorf("unexpected image: %q", img)
+	}
+}
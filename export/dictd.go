@@ -0,0 +1,106 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const dictdB64 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// dictdNum encodes n the way dictd expects offsets and lengths in its index.
+func dictdNum(n uint64) string {
+	if n == 0 {
+		return dictdB64[:1]
+	}
+	b := make([]byte, 0, 11)
+	for ; n != 0; n >>= 6 {
+		b = append(b, dictdB64[n&63])
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+type dictdIndex struct {
+	word           string
+	offset, length uint64
+}
+
+// Dictd writes a dictd database (.index and .dict) to dir.
+//
+// The database is flagged as utf8 and allchars, dictd will thus only
+// case fold headwords when comparing, which is the order the index is sorted
+// in.
+func Dictd(dir string, info Info, entries Entries) error {
+	headers := []struct{ word, text string }{
+		{"00-database-utf8", ""},
+		{"00-database-allchars", ""},
+		{"00-database-short", info.Title},
+		{"00-database-url", info.URL},
+		{"00-database-info", info.Description},
+	}
+
+	index := make([]dictdIndex, 0, len(entries)*4)
+	base := filepath.Join(dir, info.Name)
+	err := create(base+".dict", func(w io.Writer) error {
+		var offset uint64
+		add := func(word, text string) (uint64, error) {
+			n, err := io.WriteString(w, text)
+			if err != nil {
+				return 0, err
+			}
+			index = append(index, dictdIndex{word, offset, uint64(n)})
+			offset += uint64(n)
+			return uint64(n), nil
+		}
+
+		for _, h := range headers {
+			if _, err := add(h.word, fmt.Sprintf("%s\n%s\n", h.word, h.text)); err != nil {
+				return err
+			}
+		}
+
+		for _, e := range entries {
+			start := offset
+			n, err := add(e.Word, e.Text)
+			if err != nil {
+				return err
+			}
+			for _, s := range e.Synonyms {
+				index = append(index, dictdIndex{s, start, n})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(index, func(i, j int) bool {
+		a, b := strings.ToLower(index[i].word), strings.ToLower(index[j].word)
+		if a == b {
+			return index[i].word < index[j].word
+		}
+		return a < b
+	})
+
+	return create(base+".index", func(w io.Writer) error {
+		for _, i := range index {
+			_, err := fmt.Fprintf(
+				w,
+				"%s\t%s\t%s\n",
+				i.word,
+				dictdNum(i.offset),
+				dictdNum(i.length),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
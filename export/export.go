@@ -0,0 +1,51 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+type Info struct {
+	// Name is used as the basename of all generated files.
+	Name        string
+	Title       string
+	Description string
+	URL         string
+}
+
+type Entry struct {
+	Word     string
+	Synonyms []string
+	Text     string
+}
+
+type Entries []*Entry
+
+type Render func(w *openrussian.Word) (string, error)
+
+// NewEntries renders every word using render and uses its unstressed
+// inflected forms as synonyms.
+func NewEntries(words openrussian.Words, render Render) (Entries, error) {
+	entries := make(Entries, 0, len(words))
+	for _, w := range SortedWords(words) {
+		text, err := render(w)
+		if err != nil {
+			return nil, err
+		}
+
+		e := &Entry{Word: w.Word, Text: strings.TrimSpace(text) + "\n"}
+		seen := map[string]struct{}{w.Word: {}}
+		for _, f := range w.Forms() {
+			syn := f.Unstressed()
+			if _, ok := seen[syn]; ok {
+				continue
+			}
+			seen[syn] = struct{}{}
+			e.Synonyms = append(e.Synonyms, syn)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
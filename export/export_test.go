@@ -0,0 +1,113 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDictdNum(t *testing.T) {
+	tests := []struct {
+		n   uint64
+		exp string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{63, "/"},
+		{64, "BA"},
+		{4095, "//"},
+		{4096, "BAA"},
+	}
+
+	for _, d := range tests {
+		if got := dictdNum(d.n); got != d.exp {
+			t.Errorf("dictdNum(%d): exp: %s got: %s", d.n, d.exp, got)
+		}
+	}
+}
+
+func TestStarDict(t *testing.T) {
+	dir := t.TempDir()
+	entries := Entries{
+		{Word: "стол", Text: "table\n", Synonyms: []string{"стола"}},
+		{Word: "Bb", Text: "b\n"},
+		{Word: "aa", Text: "a\n"},
+	}
+
+	if err := StarDict(dir, Info{Name: "test"}, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := os.ReadFile(filepath.Join(dir, "test.idx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict, err := os.ReadFile(filepath.Join(dir, "test.dict"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []struct{ word, text string }{{"aa", "a\n"}, {"Bb", "b\n"}, {"стол", "table\n"}}
+	for _, e := range exp {
+		i := bytes.IndexByte(idx, 0)
+		if i < 0 || string(idx[:i]) != e.word {
+			t.Fatalf("expected %s in idx", e.word)
+		}
+		offset := binary.BigEndian.Uint32(idx[i+1:])
+		size := binary.BigEndian.Uint32(idx[i+5:])
+		if text := string(dict[offset : offset+size]); text != e.text {
+			t.Errorf("%s: exp: %q got: %q", e.word, e.text, text)
+		}
+		idx = idx[i+9:]
+	}
+
+	syn, err := os.ReadFile(filepath.Join(dir, "test.syn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(syn, append([]byte("стола\x00"), 0, 0, 0, 2)) {
+		t.Errorf("unexpected syn file: %q", syn)
+	}
+}
+
+func TestWriteLookups(t *testing.T) {
+	lookups := []*Lookup{
+		{Query: "стол", Results: []*Result{{ID: 3, Word: "стол", Translations: []string{"table", "desk"}}}},
+		{Query: "qqq", Fuzzy: true, Results: []*Result{}},
+	}
+
+	tests := []struct {
+		format string
+		exp    string
+	}{
+		{
+			"json",
+			`[{"query":"стол","fuzzy":false,"results":[{"id":3,"word":"стол","stressed":"","type":"","gender":"","aspect":"","level":"","translations":["table","desk"],"score":0}]},{"query":"qqq","fuzzy":true,"results":[]}]
+`,
+		},
+		{
+			"jsonl",
+			`{"query":"стол","id":3,"word":"стол","stressed":"","type":"","gender":"","aspect":"","level":"","translations":["table","desk"],"score":0}
+{"query":"qqq","id":0,"word":"","stressed":"","type":"","gender":"","aspect":"","level":"","translations":[],"score":0}
+`,
+		},
+		{
+			"tsv",
+			"query\tid\tword\tstressed\ttype\tgender\taspect\tlevel\tscore\ttranslations\n" +
+				"стол\t3\tстол\t\t\t\t\t\t0\ttable; desk\n" +
+				"qqq\t\t\t\t\t\t\t\t\t\n",
+		},
+	}
+
+	for _, test := range tests {
+		buf := bytes.NewBuffer(nil)
+		if err := WriteLookups(buf, test.format, lookups); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != test.exp {
+			t.Errorf("%s: exp:\n%s\ngot:\n%s", test.format, test.exp, got)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+// JSONWord is the JSON representation of a word.
+//
+// All stressed strings mark the stressed vowel with U+0301.
+// Fields that are empty or unknown are omitted.
+type JSONWord struct {
+	ID          openrussian.ID `json:"id"`
+	Word        string         `json:"word"`
+	Stressed    string         `json:"stressed"`
+	Type        string         `json:"type"`
+	Level       string         `json:"level,omitempty"`
+	Rank        uint64         `json:"rank,omitempty"`
+	DerivedFrom openrussian.ID `json:"derived_from,omitempty"`
+
+	// Usage notes keyed by language code.
+	Usage        map[string]string  `json:"usage,omitempty"`
+	Translations []*JSONTranslation `json:"translations"`
+
+	Noun      *JSONNoun      `json:"noun,omitempty"`
+	Adjective *JSONAdjective `json:"adjective,omitempty"`
+	Verb      *JSONVerb      `json:"verb,omitempty"`
+}
+
+type JSONTranslation struct {
+	Lang               string `json:"lang"`
+	Translation        string `json:"translation"`
+	Example            string `json:"example,omitempty"`
+	ExampleTranslation string `json:"example_translation,omitempty"`
+	Info               string `json:"info,omitempty"`
+}
+
+type JSONDeclension struct {
+	Nom  []string `json:"nom,omitempty"`
+	Gen  []string `json:"gen,omitempty"`
+	Dat  []string `json:"dat,omitempty"`
+	Acc  []string `json:"acc,omitempty"`
+	Inst []string `json:"inst,omitempty"`
+	Prep []string `json:"prep,omitempty"`
+}
+
+type JSONNoun struct {
+	// Gender is one of masculine, feminine, neuter or plural.
+	Gender       string          `json:"gender,omitempty"`
+	SingularOnly bool            `json:"singular_only,omitempty"`
+	PluralOnly   bool            `json:"plural_only,omitempty"`
+	Singular     *JSONDeclension `json:"singular,omitempty"`
+	Plural       *JSONDeclension `json:"plural,omitempty"`
+}
+
+type JSONAdjectiveGender struct {
+	Short      []string        `json:"short,omitempty"`
+	Declension *JSONDeclension `json:"declension,omitempty"`
+}
+
+type JSONAdjective struct {
+	Incomparable bool                 `json:"incomparable,omitempty"`
+	Comparative  []string             `json:"comparative,omitempty"`
+	Superlative  []string             `json:"superlative,omitempty"`
+	M            *JSONAdjectiveGender `json:"m,omitempty"`
+	F            *JSONAdjectiveGender `json:"f,omitempty"`
+	N            *JSONAdjectiveGender `json:"n,omitempty"`
+	Pl           *JSONAdjectiveGender `json:"pl,omitempty"`
+}
+
+type JSONConjugation struct {
+	Sg1 string `json:"sg1,omitempty"`
+	Sg2 string `json:"sg2,omitempty"`
+	Sg3 string `json:"sg3,omitempty"`
+	Pl1 string `json:"pl1,omitempty"`
+	Pl2 string `json:"pl2,omitempty"`
+	Pl3 string `json:"pl3,omitempty"`
+}
+
+type JSONVerb struct {
+	// Aspect is one of imperfective, perfective or both.
+	Aspect       string           `json:"aspect,omitempty"`
+	ImperativeSg string           `json:"imperative_sg,omitempty"`
+	ImperativePl string           `json:"imperative_pl,omitempty"`
+	PastM        string           `json:"past_m,omitempty"`
+	PastF        string           `json:"past_f,omitempty"`
+	PastN        string           `json:"past_n,omitempty"`
+	PastPl       string           `json:"past_pl,omitempty"`
+	Conjugation  *JSONConjugation `json:"conjugation,omitempty"`
+
+	// Participles are references to other words by id.
+	ActivePresent  openrussian.ID `json:"active_present,omitempty"`
+	ActivePast     openrussian.ID `json:"active_past,omitempty"`
+	PassivePresent openrussian.ID `json:"passive_present,omitempty"`
+	PassivePast    openrussian.ID `json:"passive_past,omitempty"`
+}
+
+func jsonStressed(s openrussian.Stressed) string { return s.String() }
+
+func jsonStressedList(l openrussian.StressedList) []string {
+	if len(l) == 0 {
+		return nil
+	}
+	n := make([]string, len(l))
+	for i := range l {
+		n[i] = jsonStressed(l[i])
+	}
+	return n
+}
+
+func jsonID(w *openrussian.Word) openrussian.ID {
+	if w == nil {
+		return 0
+	}
+	return w.ID
+}
+
+func newJSONDeclension(d *openrussian.Declension) *JSONDeclension {
+	if d == nil {
+		return nil
+	}
+	return &JSONDeclension{
+		Nom:  jsonStressedList(d.Nom),
+		Gen:  jsonStressedList(d.Gen),
+		Dat:  jsonStressedList(d.Dat),
+		Acc:  jsonStressedList(d.Acc),
+		Inst: jsonStressedList(d.Inst),
+		Prep: jsonStressedList(d.Prep),
+	}
+}
+
+func newJSONAdjectiveGender(g *openrussian.AdjGenderInfo) *JSONAdjectiveGender {
+	if g == nil {
+		return nil
+	}
+	return &JSONAdjectiveGender{
+		Short:      jsonStressedList(g.Short),
+		Declension: newJSONDeclension(g.Decl),
+	}
+}
+
+// NewJSONWord converts w to its JSON representation.
+func NewJSONWord(w *openrussian.Word) *JSONWord {
+	j := &JSONWord{
+		ID:           w.ID,
+		Word:         w.Word,
+		Stressed:     jsonStressed(w.Stressed),
+		Type:         w.WordType.Name(),
+		Level:        w.LanguageLevel.String(),
+		Rank:         w.Rank,
+		DerivedFrom:  jsonID(w.DerivedFrom),
+		Usage:        w.Usages,
+		Translations: make([]*JSONTranslation, len(w.Translations)),
+	}
+
+	for i, t := range w.Translations {
+		j.Translations[i] = &JSONTranslation{
+			Lang:               t.Lang,
+			Translation:        t.Translation,
+			Example:            t.Example,
+			ExampleTranslation: t.ExampleTranslation,
+			Info:               t.Info,
+		}
+	}
+
+	if n := w.NounInfo; n != nil {
+		j.Noun = &JSONNoun{
+			Gender:       n.Gender.String(),
+			SingularOnly: n.SingularOnly,
+			PluralOnly:   n.PluralOnly,
+			Singular:     newJSONDeclension(n.Singular),
+			Plural:       newJSONDeclension(n.Plural),
+		}
+	}
+
+	if a := w.AdjInfo; a != nil {
+		j.Adjective = &JSONAdjective{
+			Incomparable: a.Incomparable,
+			Comparative:  jsonStressedList(a.Comparative),
+			Superlative:  jsonStressedList(a.Superlative),
+			M:            newJSONAdjectiveGender(a.M),
+			F:            newJSONAdjectiveGender(a.F),
+			N:            newJSONAdjectiveGender(a.N),
+			Pl:           newJSONAdjectiveGender(a.Pl),
+		}
+	}
+
+	if v := w.VerbInfo; v != nil {
+		j.Verb = &JSONVerb{
+			Aspect:         v.Aspect.String(),
+			ImperativeSg:   jsonStressed(v.ImperativeSg),
+			ImperativePl:   jsonStressed(v.ImperativePl),
+			PastM:          jsonStressed(v.PastM),
+			PastF:          jsonStressed(v.PastF),
+			PastN:          jsonStressed(v.PastN),
+			PastPl:         jsonStressed(v.PastPl),
+			ActivePresent:  jsonID(v.ActivePresent),
+			ActivePast:     jsonID(v.ActivePast),
+			PassivePresent: jsonID(v.PassivePresent),
+			PassivePast:    jsonID(v.PassivePast),
+		}
+		if c := v.Conjugation; c != nil {
+			j.Verb.Conjugation = &JSONConjugation{
+				Sg1: jsonStressed(c.Sg1),
+				Sg2: jsonStressed(c.Sg2),
+				Sg3: jsonStressed(c.Sg3),
+				Pl1: jsonStressed(c.Pl1),
+				Pl2: jsonStressed(c.Pl2),
+				Pl3: jsonStressed(c.Pl3),
+			}
+		}
+	}
+
+	return j
+}
+
+// SortedWords returns all words ordered by id.
+func SortedWords(words openrussian.Words) []*openrussian.Word {
+	l := make([]*openrussian.Word, 0, len(words))
+	for _, w := range words {
+		l = append(l, w)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].ID < l[j].ID })
+	return l
+}
+
+// JSONL writes one JSONWord per line.
+func JSONL(w io.Writer, words openrussian.Words) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, word := range SortedWords(words) {
+		if err := enc.Encode(NewJSONWord(word)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SortByRank sorts words by rank, words without a rank go last.
+func SortByRank(words []*openrussian.Word) {
+	sort.SliceStable(words, func(i, j int) bool {
+		a, b := words[i].Rank, words[j].Rank
+		if a == 0 || b == 0 {
+			return a != 0
+		}
+		return a < b
+	})
+}
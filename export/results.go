@@ -0,0 +1,242 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/openrussian"
+)
+
+// ResultFormats are the supported output formats for lookups.
+var ResultFormats = []string{"json", "jsonl", "tsv", "md"}
+
+// Lookup holds the results for a single query.
+//
+// The JSON representation of Lookup and Result is a stable schema, see
+// the README for a description.
+type Lookup struct {
+	Query   string    `json:"query"`
+	Fuzzy   bool      `json:"fuzzy"`
+	Results []*Result `json:"results"`
+}
+
+// Result is a single word matching a query.
+//
+// Fields that do not apply to the word are empty strings, translations is
+// never null.
+type Result struct {
+	ID           openrussian.ID `json:"id"`
+	Word         string         `json:"word"`
+	Stressed     string         `json:"stressed"`
+	Type         string         `json:"type"`
+	Gender       string         `json:"gender"`
+	Aspect       string         `json:"aspect"`
+	Level        string         `json:"level"`
+	Translations []string       `json:"translations"`
+
+	// Score is how far the word is from the query, 0 being an exact match.
+	Score int `json:"score"`
+}
+
+type resultLine struct {
+	Query string `json:"query"`
+	*Result
+}
+
+func NewResult(w *openrussian.Word, score int) *Result {
+	r := &Result{
+		ID:           w.ID,
+		Word:         w.Word,
+		Stressed:     w.Stressed.String(),
+		Type:         w.WordType.Name(),
+		Level:        w.LanguageLevel.String(),
+		Translations: make([]string, 0, len(w.Translations)),
+		Score:        score,
+	}
+	if w.NounInfo != nil {
+		r.Gender = w.NounInfo.Gender.String()
+	}
+	if w.VerbInfo != nil {
+		r.Aspect = w.VerbInfo.Aspect.String()
+	}
+	for _, t := range w.Translations {
+		r.Translations = append(r.Translations, t.Translation)
+	}
+	return r
+}
+
+func NewLookup(query string, fuzzy bool, results dict.Results) *Lookup {
+	l := &Lookup{Query: query, Fuzzy: fuzzy, Results: make([]*Result, len(results))}
+	for i, r := range results {
+		l.Results[i] = NewResult(r.Word, r.Distance())
+	}
+	return l
+}
+
+// LookupWriter writes lookups in one of the ResultFormats:
+//
+//	json:  an array of Lookup objects, written on Close
+//	jsonl: one Result object per line with an additional query field
+//	tsv:   a header followed by a row per result
+//	md:    a markdown table with a row per result
+//
+// Lookups without results are written as a row with only the query.
+// Close must be called after the last Write.
+type LookupWriter struct {
+	w       io.Writer
+	format  string
+	enc     *json.Encoder
+	lookups []*Lookup
+	row     func([]string) string
+	sep     bool
+	header  bool
+}
+
+func NewLookupWriter(w io.Writer, format string) (*LookupWriter, error) {
+	l := &LookupWriter{w: w, format: format}
+	switch format {
+	case "json", "jsonl":
+		l.enc = json.NewEncoder(w)
+		l.enc.SetEscapeHTML(false)
+	case "tsv":
+		l.row = tsvRow
+	case "md":
+		l.row, l.sep = mdRow, true
+	default:
+		return nil, fmt.Errorf("unknown format '%s', available: %s", format, strings.Join(ResultFormats, ", "))
+	}
+	return l, nil
+}
+
+func (l *LookupWriter) Write(lookup *Lookup) error {
+	switch l.format {
+	case "json":
+		l.lookups = append(l.lookups, lookup)
+		return nil
+	case "jsonl":
+		if len(lookup.Results) == 0 {
+			return l.enc.Encode(resultLine{lookup.Query, &Result{Translations: []string{}}})
+		}
+		for _, r := range lookup.Results {
+			if err := l.enc.Encode(resultLine{lookup.Query, r}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !l.header {
+		l.header = true
+		if err := l.writeRow(tableHeader); err != nil {
+			return err
+		}
+		if l.sep {
+			sep := make([]string, len(tableHeader))
+			for i := range sep {
+				sep[i] = "---"
+			}
+			if err := l.writeRow(sep); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(lookup.Results) == 0 {
+		return l.writeRow(tableRow(lookup.Query, nil))
+	}
+	for _, r := range lookup.Results {
+		if err := l.writeRow(tableRow(lookup.Query, r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close writes the buffered lookups of the json format.
+func (l *LookupWriter) Close() error {
+	if l.format != "json" {
+		return nil
+	}
+	lookups := l.lookups
+	if lookups == nil {
+		lookups = []*Lookup{}
+	}
+	l.lookups = nil
+	return l.enc.Encode(lookups)
+}
+
+func (l *LookupWriter) writeRow(row []string) error {
+	n := make([]string, len(row))
+	copy(n, row)
+	_, err := io.WriteString(l.w, l.row(n))
+	return err
+}
+
+// WriteLookups writes all lookups using a LookupWriter.
+func WriteLookups(w io.Writer, format string, lookups []*Lookup) error {
+	lw, err := NewLookupWriter(w, format)
+	if err != nil {
+		return err
+	}
+	for _, l := range lookups {
+		if err := lw.Write(l); err != nil {
+			return err
+		}
+	}
+	return lw.Close()
+}
+
+var tableHeader = []string{
+	"query",
+	"id",
+	"word",
+	"stressed",
+	"type",
+	"gender",
+	"aspect",
+	"level",
+	"score",
+	"translations",
+}
+
+func tableRow(query string, r *Result) []string {
+	if r == nil {
+		row := make([]string, len(tableHeader))
+		row[0] = query
+		return row
+	}
+	return []string{
+		query,
+		strconv.FormatUint(uint64(r.ID), 10),
+		r.Word,
+		r.Stressed,
+		r.Type,
+		r.Gender,
+		r.Aspect,
+		r.Level,
+		strconv.Itoa(r.Score),
+		strings.Join(r.Translations, "; "),
+	}
+}
+
+var tsvReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", "")
+
+func tsvRow(row []string) string {
+	for i := range row {
+		row[i] = tsvReplacer.Replace(row[i])
+	}
+	return strings.Join(row, "\t") + "\n"
+}
+
+var mdReplacer = strings.NewReplacer("|", "\\|", "\n", " ", "\r", "")
+
+func mdRow(row []string) string {
+	for i := range row {
+		row[i] = mdReplacer.Replace(row[i])
+	}
+	return "| " + strings.Join(row, " | ") + " |\n"
+}
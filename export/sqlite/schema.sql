@@ -0,0 +1,126 @@
+-- goru dictionary database
+--
+-- All stressed strings mark the stressed vowel with U+0301.
+-- Lists of alternative forms are stored comma separated.
+
+-- words holds every dictionary entry.
+CREATE TABLE words (
+    id           INTEGER PRIMARY KEY,
+    position     INTEGER,
+    rank         INTEGER,
+    word         TEXT NOT NULL,  -- unstressed
+    lower        TEXT NOT NULL,  -- unstressed, lowercase
+    stressed     TEXT NOT NULL,
+    type         TEXT NOT NULL,  -- other, adjective, adverb, expression, noun, verb
+    level        TEXT,           -- A1 - C2 or NULL
+    derived_from INTEGER REFERENCES words(id)
+);
+CREATE INDEX words_lower ON words(lower);
+CREATE INDEX words_derived_from ON words(derived_from);
+
+-- usages holds usage notes (e.g.: colloquial, obsolete) per language.
+CREATE TABLE usages (
+    word_id INTEGER NOT NULL REFERENCES words(id),
+    lang    TEXT NOT NULL,
+    usage   TEXT NOT NULL,
+    PRIMARY KEY (word_id, lang)
+);
+
+-- translations holds the translations of a word in all languages.
+CREATE TABLE translations (
+    id                  INTEGER PRIMARY KEY,
+    word_id             INTEGER NOT NULL REFERENCES words(id),
+    lang                TEXT NOT NULL,
+    translation         TEXT NOT NULL,
+    example             TEXT,
+    example_translation TEXT,
+    info                TEXT
+);
+CREATE INDEX translations_word ON translations(word_id);
+CREATE INDEX translations_lang ON translations(lang);
+
+-- declensions holds the six cases of a noun or adjective declension.
+CREATE TABLE declensions (
+    id   INTEGER PRIMARY KEY,
+    nom  TEXT,
+    gen  TEXT,
+    dat  TEXT,
+    acc  TEXT,
+    inst TEXT,
+    prep TEXT
+);
+
+CREATE TABLE nouns (
+    word_id       INTEGER PRIMARY KEY REFERENCES words(id),
+    gender        TEXT,  -- masculine, feminine, neuter, plural or NULL
+    singular_only INTEGER NOT NULL,
+    plural_only   INTEGER NOT NULL,
+    singular_id   INTEGER REFERENCES declensions(id),
+    plural_id     INTEGER REFERENCES declensions(id)
+);
+
+CREATE TABLE adjectives (
+    word_id      INTEGER PRIMARY KEY REFERENCES words(id),
+    incomparable INTEGER NOT NULL,
+    comparative  TEXT,
+    superlative  TEXT
+);
+
+-- adjective_genders holds the short form and declension of an adjective
+-- for a single gender.
+CREATE TABLE adjective_genders (
+    word_id        INTEGER NOT NULL REFERENCES words(id),
+    gender         TEXT NOT NULL,  -- masculine, feminine, neuter or plural
+    short          TEXT,
+    declension_id  INTEGER REFERENCES declensions(id),
+    PRIMARY KEY (word_id, gender)
+);
+
+-- conjugations holds the present (imperfective) or future (perfective) tense.
+CREATE TABLE conjugations (
+    id  INTEGER PRIMARY KEY,
+    sg1 TEXT,
+    sg2 TEXT,
+    sg3 TEXT,
+    pl1 TEXT,
+    pl2 TEXT,
+    pl3 TEXT
+);
+
+CREATE TABLE verbs (
+    word_id            INTEGER PRIMARY KEY REFERENCES words(id),
+    aspect             TEXT,  -- imperfective, perfective, both or NULL
+    imperative_sg      TEXT,
+    imperative_pl      TEXT,
+    past_m             TEXT,
+    past_f             TEXT,
+    past_n             TEXT,
+    past_pl            TEXT,
+    conjugation_id     INTEGER REFERENCES conjugations(id),
+    active_present_id  INTEGER REFERENCES words(id),
+    active_past_id     INTEGER REFERENCES words(id),
+    passive_present_id INTEGER REFERENCES words(id),
+    passive_past_id    INTEGER REFERENCES words(id)
+);
+
+CREATE TABLE sentences (
+    id    INTEGER PRIMARY KEY,
+    ru    TEXT NOT NULL,
+    level TEXT
+);
+
+CREATE TABLE sentence_translations (
+    sentence_id INTEGER NOT NULL REFERENCES sentences(id),
+    lang        TEXT NOT NULL,
+    translation TEXT NOT NULL
+);
+CREATE INDEX sentence_translations_sentence ON sentence_translations(sentence_id);
+
+-- sentence_words links sentences to the words they contain.
+CREATE TABLE sentence_words (
+    sentence_id INTEGER NOT NULL REFERENCES sentences(id),
+    word_id     INTEGER NOT NULL REFERENCES words(id),
+    position    INTEGER NOT NULL,
+    PRIMARY KEY (sentence_id, position)
+);
+CREATE INDEX sentence_words_word ON sentence_words(word_id);
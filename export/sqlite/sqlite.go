@@ -0,0 +1,321 @@
+package sqlite
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/frizinak/goru/export"
+	"github.com/frizinak/goru/openrussian"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Schema is the documented schema of the generated database.
+//
+//go:embed schema.sql
+var Schema string
+
+func null(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullID(w *openrussian.Word) interface{} {
+	if w == nil {
+		return nil
+	}
+	return w.ID
+}
+
+func stressedList(l openrussian.StressedList) interface{} {
+	if len(l) == 0 {
+		return nil
+	}
+	return l.String()
+}
+
+func stressed(s openrussian.Stressed) interface{} {
+	return null(s.String())
+}
+
+func boolean(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type stmts map[string]*sql.Stmt
+
+func (s stmts) exec(name string, args ...interface{}) (sql.Result, error) {
+	return s[name].Exec(args...)
+}
+
+var queries = map[string]string{
+	"word":            `INSERT INTO words VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	"usage":           `INSERT INTO usages VALUES (?, ?, ?)`,
+	"translation":     `INSERT INTO translations (word_id, lang, translation, example, example_translation, info) VALUES (?, ?, ?, ?, ?, ?)`,
+	"declension":      `INSERT INTO declensions (nom, gen, dat, acc, inst, prep) VALUES (?, ?, ?, ?, ?, ?)`,
+	"noun":            `INSERT INTO nouns VALUES (?, ?, ?, ?, ?, ?)`,
+	"adjective":       `INSERT INTO adjectives VALUES (?, ?, ?, ?)`,
+	"adjectiveGender": `INSERT INTO adjective_genders VALUES (?, ?, ?, ?)`,
+	"conjugation":     `INSERT INTO conjugations (sg1, sg2, sg3, pl1, pl2, pl3) VALUES (?, ?, ?, ?, ?, ?)`,
+	"verb":            `INSERT INTO verbs VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	"sentence":        `INSERT INTO sentences VALUES (?, ?, ?)`,
+	"sentenceTrans":   `INSERT INTO sentence_translations VALUES (?, ?, ?)`,
+	"sentenceWord":    `INSERT INTO sentence_words VALUES (?, ?, ?)`,
+}
+
+// Export writes all words and their sentences to a new sqlite database
+// at path, replacing any existing file.
+func Export(path string, words openrussian.Words) error {
+	tmp := fmt.Sprintf("%s.%d.tmp", path, time.Now().UnixNano())
+	db, err := sql.Open("sqlite3", tmp)
+	if err != nil {
+		return err
+	}
+
+	if err := write(db, words); err != nil {
+		db.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := db.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func write(db *sql.DB, words openrussian.Words) error {
+	if _, err := db.Exec(Schema); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	s := make(stmts, len(queries))
+	for n, q := range queries {
+		stmt, err := tx.Prepare(q)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		defer stmt.Close()
+		s[n] = stmt
+	}
+
+	if err := insert(s, words); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insert(s stmts, words openrussian.Words) error {
+	declension := func(d *openrussian.Declension) (interface{}, error) {
+		if d == nil {
+			return nil, nil
+		}
+		res, err := s.exec(
+			"declension",
+			stressedList(d.Nom),
+			stressedList(d.Gen),
+			stressedList(d.Dat),
+			stressedList(d.Acc),
+			stressedList(d.Inst),
+			stressedList(d.Prep),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return res.LastInsertId()
+	}
+
+	sorted := export.SortedWords(words)
+	for _, w := range sorted {
+		var derived interface{}
+		if w.DerivedFrom != nil && words[w.DerivedFrom.ID] != nil {
+			derived = w.DerivedFrom.ID
+		}
+		_, err := s.exec(
+			"word",
+			w.ID,
+			w.Position,
+			w.Rank,
+			w.Word,
+			w.Lower,
+			w.Stressed.String(),
+			w.WordType.Name(),
+			null(w.LanguageLevel.String()),
+			derived,
+		)
+		if err != nil {
+			return err
+		}
+
+		langs := make([]string, 0, len(w.Usages))
+		for lang := range w.Usages {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+		for _, lang := range langs {
+			if _, err := s.exec("usage", w.ID, lang, w.Usages[lang]); err != nil {
+				return err
+			}
+		}
+
+		for _, t := range w.Translations {
+			_, err := s.exec(
+				"translation",
+				w.ID,
+				t.Lang,
+				t.Translation,
+				null(t.Example),
+				null(t.ExampleTranslation),
+				null(t.Info),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if n := w.NounInfo; n != nil {
+			sg, err := declension(n.Singular)
+			if err != nil {
+				return err
+			}
+			pl, err := declension(n.Plural)
+			if err != nil {
+				return err
+			}
+			_, err = s.exec(
+				"noun",
+				w.ID,
+				null(n.Gender.String()),
+				boolean(n.SingularOnly),
+				boolean(n.PluralOnly),
+				sg,
+				pl,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if a := w.AdjInfo; a != nil {
+			_, err := s.exec(
+				"adjective",
+				w.ID,
+				boolean(a.Incomparable),
+				stressedList(a.Comparative),
+				stressedList(a.Superlative),
+			)
+			if err != nil {
+				return err
+			}
+			for _, g := range []*openrussian.AdjGenderInfo{a.M, a.F, a.N, a.Pl} {
+				if g == nil {
+					continue
+				}
+				decl, err := declension(g.Decl)
+				if err != nil {
+					return err
+				}
+				_, err = s.exec(
+					"adjectiveGender",
+					w.ID,
+					g.Gender.String(),
+					stressedList(g.Short),
+					decl,
+				)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if v := w.VerbInfo; v != nil {
+			var conj interface{}
+			if c := v.Conjugation; c != nil {
+				res, err := s.exec(
+					"conjugation",
+					stressed(c.Sg1),
+					stressed(c.Sg2),
+					stressed(c.Sg3),
+					stressed(c.Pl1),
+					stressed(c.Pl2),
+					stressed(c.Pl3),
+				)
+				if err != nil {
+					return err
+				}
+				if conj, err = res.LastInsertId(); err != nil {
+					return err
+				}
+			}
+
+			_, err := s.exec(
+				"verb",
+				w.ID,
+				null(v.Aspect.String()),
+				stressed(v.ImperativeSg),
+				stressed(v.ImperativePl),
+				stressed(v.PastM),
+				stressed(v.PastF),
+				stressed(v.PastN),
+				stressed(v.PastPl),
+				conj,
+				nullID(v.ActivePresent),
+				nullID(v.ActivePast),
+				nullID(v.PassivePresent),
+				nullID(v.PassivePast),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	sentences := words.Sentences()
+	ids := make([]openrussian.ID, 0, len(sentences))
+	for id := range sentences {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		sentence := sentences[id]
+		_, err := s.exec(
+			"sentence",
+			sentence.ID,
+			sentence.Russian.String(),
+			null(sentence.LanguageLevel.String()),
+		)
+		if err != nil {
+			return err
+		}
+		for _, t := range sentence.Translations {
+			if _, err := s.exec("sentenceTrans", sentence.ID, t.Lang, t.Translation); err != nil {
+				return err
+			}
+		}
+		for i, w := range sentence.Words() {
+			if _, err := s.exec("sentenceWord", sentence.ID, w.ID, i); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+func testWords() openrussian.Words {
+	decl := func(s ...openrussian.Stressed) *openrussian.Declension {
+		l := func(s openrussian.Stressed) openrussian.StressedList { return openrussian.StressedList{s} }
+		return &openrussian.Declension{Nom: l(s[0]), Gen: l(s[1]), Dat: l(s[2]), Acc: l(s[3]), Inst: l(s[4]), Prep: l(s[5])}
+	}
+
+	table := &openrussian.Word{
+		ID:       1,
+		Word:     "стол",
+		Lower:    "стол",
+		Stressed: "сто'л",
+		WordType: openrussian.Noun,
+		Translations: []*openrussian.Translation{
+			{Lang: "en", Translation: "table"},
+			{Lang: "de", Translation: "Tisch"},
+		},
+		NounInfo: &openrussian.NounInfo{
+			Gender:   openrussian.M,
+			Singular: decl("сто'л", "стола'", "столу'", "сто'л", "столо'м", "столе'"),
+			Plural:   decl("столы'", "столо'в", "стола'м", "столы'", "стола'ми", "стола'х"),
+		},
+	}
+	walk := &openrussian.Word{
+		ID:           2,
+		Word:         "ходить",
+		Lower:        "ходить",
+		Stressed:     "ходи'ть",
+		WordType:     openrussian.Verb,
+		Translations: []*openrussian.Translation{{Lang: "en", Translation: "to walk"}},
+		VerbInfo: &openrussian.VerbInfo{
+			Aspect: openrussian.Imperfective,
+			PastM:  "ходи'л",
+			Conjugation: &openrussian.Conjugation{
+				Sg1: "хожу'", Sg2: "хо'дишь", Sg3: "хо'дит",
+				Pl1: "хо'дим", Pl2: "хо'дите", Pl3: "хо'дят",
+			},
+		},
+	}
+	come := &openrussian.Word{
+		ID:          3,
+		Word:        "приходить",
+		Lower:       "приходить",
+		Stressed:    "приходи'ть",
+		WordType:    openrussian.Verb,
+		DerivedFrom: walk,
+	}
+
+	return openrussian.Words{1: table, 2: walk, 3: come}
+}
+
+func TestExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goru.sqlite")
+	if err := Export(path, testWords()); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	query := func(q string, dst ...interface{}) {
+		t.Helper()
+		if err := db.QueryRow(q).Scan(dst...); err != nil {
+			t.Fatalf("%s: %s", q, err)
+		}
+	}
+
+	counts := map[string]int{
+		"words":        3,
+		"translations": 3,
+		"nouns":        1,
+		"declensions":  2,
+		"verbs":        1,
+		"conjugations": 1,
+		"adjectives":   0,
+		"sentences":    0,
+	}
+	for table, exp := range counts {
+		var n int
+		query("SELECT COUNT(*) FROM "+table, &n)
+		if n != exp {
+			t.Errorf("%s: expected %d rows got %d", table, exp, n)
+		}
+	}
+
+	var derived string
+	query(`SELECT p.word FROM words w JOIN words p ON p.id = w.derived_from WHERE w.word = 'приходить'`, &derived)
+	if derived != "ходить" {
+		t.Errorf("expected приходить to be derived from ходить, got %s", derived)
+	}
+
+	var roots int
+	query(`SELECT COUNT(*) FROM words WHERE derived_from IS NULL`, &roots)
+	if roots != 2 {
+		t.Errorf("expected 2 underived words got %d", roots)
+	}
+
+	var gender, gen, inst string
+	query(`SELECT n.gender, s.gen, p.inst FROM nouns n
+		JOIN declensions s ON s.id = n.singular_id
+		JOIN declensions p ON p.id = n.plural_id
+		WHERE n.word_id = 1`, &gender, &gen, &inst)
+	if gender != "masculine" || gen != "стола́" || inst != "стола́ми" {
+		t.Errorf("unexpected noun: %s %s %s", gender, gen, inst)
+	}
+
+	var aspect, sg1, pl3 string
+	query(`SELECT v.aspect, c.sg1, c.pl3 FROM verbs v
+		JOIN conjugations c ON c.id = v.conjugation_id
+		WHERE v.word_id = 2`, &aspect, &sg1, &pl3)
+	if aspect != "imperfective" || sg1 != "хожу́" || pl3 != "хо́дят" {
+		t.Errorf("unexpected verb: %s %s %s", aspect, sg1, pl3)
+	}
+
+	rows, err := db.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		t.Error("foreign key violations")
+	}
+}
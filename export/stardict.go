@@ -0,0 +1,138 @@
+package export
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// stardictLess mimics stardict_strcmp: an ascii case insensitive comparison
+// falling back to a byte comparison.
+func stardictLess(a, b string) bool {
+	if c := asciiCaseCmp(a, b); c != 0 {
+		return c < 0
+	}
+	return a < b
+}
+
+func asciiCaseCmp(a, b string) int {
+	lower := func(c byte) byte {
+		if c >= 'A' && c <= 'Z' {
+			return c + 'a' - 'A'
+		}
+		return c
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ca, cb := lower(a[i]), lower(b[i])
+		if ca != cb {
+			return int(ca) - int(cb)
+		}
+	}
+	return len(a) - len(b)
+}
+
+type stardictSyn struct {
+	word  string
+	index uint32
+}
+
+// StarDict writes a StarDict dictionary (.ifo, .idx, .dict and .syn) to dir.
+func StarDict(dir string, info Info, entries Entries) error {
+	sorted := make(Entries, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return stardictLess(sorted[i].Word, sorted[j].Word)
+	})
+
+	base := filepath.Join(dir, info.Name)
+	syns := make([]stardictSyn, 0, len(sorted))
+	var idxSize int64
+	err := create(base+".dict", func(dict io.Writer) error {
+		return create(base+".idx", func(idx io.Writer) error {
+			var offset uint32
+			buf := make([]byte, 8)
+			for i, e := range sorted {
+				n, err := io.WriteString(dict, e.Text)
+				if err != nil {
+					return err
+				}
+
+				binary.BigEndian.PutUint32(buf, offset)
+				binary.BigEndian.PutUint32(buf[4:], uint32(n))
+				if _, err := io.WriteString(idx, e.Word+"\x00"); err != nil {
+					return err
+				}
+				if _, err := idx.Write(buf); err != nil {
+					return err
+				}
+				idxSize += int64(len(e.Word)) + 1 + int64(len(buf))
+				offset += uint32(n)
+
+				for _, s := range e.Synonyms {
+					syns = append(syns, stardictSyn{s, uint32(i)})
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(syns, func(i, j int) bool {
+		return stardictLess(syns[i].word, syns[j].word)
+	})
+	err = create(base+".syn", func(w io.Writer) error {
+		buf := make([]byte, 4)
+		for _, s := range syns {
+			binary.BigEndian.PutUint32(buf, s.index)
+			if _, err := io.WriteString(w, s.word+"\x00"); err != nil {
+				return err
+			}
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return create(base+".ifo", func(w io.Writer) error {
+		desc := strings.ReplaceAll(info.Description, "\n", "<br>")
+		_, err := fmt.Fprintf(
+			w,
+			"StarDict's dict ifo file\nversion=3.0.0\nbookname=%s\nwordcount=%d\nsynwordcount=%d\nidxfilesize=%d\nidxoffsetbits=32\nwebsite=%s\ndescription=%s\nsametypesequence=m\n",
+			info.Title,
+			len(sorted),
+			len(syns),
+			idxSize,
+			info.URL,
+			desc,
+		)
+		return err
+	})
+}
+
+func create(path string, cb func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := cb(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
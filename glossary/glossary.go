@@ -0,0 +1,137 @@
+// Package glossary lists the words used in a russian text.
+package glossary
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/frizinak/goru/accent"
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/openrussian"
+)
+
+type Entry struct {
+	// Word is the lemma, localized in the requested language.
+	Word *openrussian.Word
+	// Count is the amount of times any form of Word occurs in the text.
+	Count int
+	// First is the index of the first occurrence in the text.
+	First int
+}
+
+// Translation returns the first translation of the word.
+func (e *Entry) Translation() string {
+	if len(e.Word.Translations) == 0 {
+		return ""
+	}
+	return e.Word.Translations[0].Translation
+}
+
+type Glossary struct {
+	Entries []*Entry
+	// Unknown are the distinct words that could not be resolved.
+	Unknown []string
+}
+
+type Sort uint8
+
+const (
+	// ByCount sorts the most frequent words first.
+	ByCount Sort = iota
+	// ByText sorts words in order of their first occurrence.
+	ByText
+	// ByAlpha sorts words alphabetically.
+	ByAlpha
+)
+
+type Options struct {
+	Lang string
+	// Above excludes words with a level at or below the given one,
+	// words without a level are always included.
+	Above openrussian.LanguageLevel
+	Sort  Sort
+}
+
+// lemma picks the most common word with a translation out of all words
+// that have the given form.
+func lemma(d *dict.Dict, form, lang string) *openrussian.Word {
+	var best *openrussian.Word
+	better := func(w *openrussian.Word) bool {
+		if best == nil {
+			return true
+		}
+		a, b := len(w.TranslationsFor(lang)) != 0, len(best.TranslationsFor(lang)) != 0
+		if a != b {
+			return a
+		}
+		if w.Rank == 0 || best.Rank == 0 {
+			return w.Rank != 0 && best.Rank == 0
+		}
+		return w.Rank < best.Rank
+	}
+
+	for _, w := range d.Lemmas(form) {
+		if better(w) {
+			best = w
+		}
+	}
+	return best
+}
+
+// New builds a glossary of every distinct lemma in text.
+func New(d *dict.Dict, text string, o Options) *Glossary {
+	if o.Lang == "" {
+		o.Lang = openrussian.DefaultLanguage
+	}
+
+	entries := make(map[openrussian.ID]*Entry)
+	unknown := make(map[string]struct{})
+	g := &Glossary{}
+	d.InitFormIndex()
+	tokens := make([]string, 0)
+	for _, token := range accent.Tokenize(text) {
+		if !accent.IsWord(token) {
+			continue
+		}
+		if strings.Contains(token, "-") && lemma(d, token, o.Lang) == nil {
+			tokens = append(tokens, strings.Split(token, "-")...)
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+
+	for i, token := range tokens {
+		w := lemma(d, token, o.Lang)
+		if w == nil {
+			k := strings.ToLower(token)
+			if _, ok := unknown[k]; !ok {
+				unknown[k] = struct{}{}
+				g.Unknown = append(g.Unknown, k)
+			}
+			continue
+		}
+		if o.Above != 0 && w.LanguageLevel != 0 && w.LanguageLevel <= o.Above {
+			continue
+		}
+		e, ok := entries[w.ID]
+		if !ok {
+			e = &Entry{Word: w.Localized(o.Lang), First: i}
+			entries[w.ID] = e
+			g.Entries = append(g.Entries, e)
+		}
+		e.Count++
+	}
+
+	switch o.Sort {
+	case ByCount:
+		sort.SliceStable(g.Entries, func(i, j int) bool {
+			return g.Entries[i].Count > g.Entries[j].Count
+		})
+	case ByAlpha:
+		sort.SliceStable(g.Entries, func(i, j int) bool {
+			return g.Entries[i].Word.Lower < g.Entries[j].Word.Lower
+		})
+	}
+
+	return g
+}
@@ -0,0 +1,115 @@
+package glossary
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/openrussian"
+)
+
+func testDict() *dict.Dict {
+	tl := func(s string) []*openrussian.Translation {
+		return []*openrussian.Translation{{Lang: "en", Translation: s}}
+	}
+	return dict.New(openrussian.Words{
+		1: {
+			ID: 1, Word: "писать", Lower: "писать", Stressed: "писа'ть", Rank: 50,
+			WordType: openrussian.Verb, LanguageLevel: openrussian.A1, Translations: tl("write"),
+			VerbInfo: &openrussian.VerbInfo{Conjugation: &openrussian.Conjugation{Sg1: "пишу'", Sg3: "пи'шет"}},
+		},
+		2: {
+			ID: 2, Word: "стол", Lower: "стол", Stressed: "сто'л", Rank: 20,
+			WordType: openrussian.Noun, LanguageLevel: openrussian.A1, Translations: tl("table"),
+			NounInfo: &openrussian.NounInfo{Singular: &openrussian.Declension{Gen: openrussian.StressedList{"стола'"}}},
+		},
+		3: {
+			ID: 3, Word: "бабка", Lower: "бабка", Stressed: "ба'бка",
+			WordType: openrussian.Noun, LanguageLevel: openrussian.B2, Translations: tl("granny"),
+		},
+		4: {ID: 4, Word: "стол", Lower: "стол", Stressed: "сто'л", WordType: openrussian.Noun},
+	})
+}
+
+const testText = "Бабка пишет. Я пишу у стола, бабка пишет за столом."
+
+func TestNew(t *testing.T) {
+	d := testDict()
+	g := New(d, testText, Options{Lang: "en"})
+
+	var got []string
+	for _, e := range g.Entries {
+		got = append(got, fmt.Sprintf("%s:%d", e.Word.Word, e.Count))
+	}
+	if exp := "писать:3 бабка:2 стол:1"; strings.Join(got, " ") != exp {
+		t.Errorf("expected %s got %s", exp, strings.Join(got, " "))
+	}
+	if exp := "я у за столом"; strings.Join(g.Unknown, " ") != exp {
+		t.Errorf("expected unknown %s got %s", exp, strings.Join(g.Unknown, " "))
+	}
+	if g.Entries[2].Word.ID != 2 {
+		t.Errorf("expected the ranked, translated стол, got %d", g.Entries[2].Word.ID)
+	}
+
+	g = New(d, testText, Options{Lang: "en", Sort: ByText})
+	if w := g.Entries[0].Word.Word; w != "бабка" {
+		t.Errorf("expected бабка first in text order, got %s", w)
+	}
+	g = New(d, testText, Options{Lang: "en", Sort: ByAlpha})
+	if w := g.Entries[0].Word.Word; w != "бабка" || g.Entries[2].Word.Word != "стол" {
+		t.Errorf("unexpected alphabetic order: %s", w)
+	}
+
+	g = New(d, testText, Options{Lang: "en", Above: openrussian.A1})
+	if len(g.Entries) != 1 || g.Entries[0].Word.Word != "бабка" {
+		t.Errorf("expected only бабка above A1, got %d entries", len(g.Entries))
+	}
+}
+
+func TestWrite(t *testing.T) {
+	g := New(testDict(), "стол | бабки", Options{Lang: "en"})
+	tests := []struct {
+		format string
+		exp    string
+	}{
+		{
+			"md",
+			"| word | count | level | type | translation |\n" +
+				"| --- | --: | --- | --- | --- |\n" +
+				"| сто́л | 1 | A1 | noun | table |\n" +
+				"\nUnknown: бабки\n",
+		},
+		{
+			"csv",
+			"word,count,level,type,translation\n" +
+				"сто́л,1,A1,noun,table\n",
+		},
+	}
+	for _, test := range tests {
+		buf := bytes.NewBuffer(nil)
+		if err := g.Write(buf, test.format); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != test.exp {
+			t.Errorf("%s: exp:\n%s\ngot:\n%s", test.format, test.exp, got)
+		}
+	}
+
+	g.Entries[0].Word.Translations[0] = &openrussian.Translation{Translation: "<b>table</b>"}
+	buf := bytes.NewBuffer(nil)
+	if err := g.Write(buf, "html"); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, s := range []string{"<td>сто́л</td>", `<td class="count">1</td>`, "&lt;b&gt;table&lt;/b&gt;", "Unknown: бабки"} {
+		if !strings.Contains(html, s) {
+			t.Errorf("expected %q in html:\n%s", s, html)
+		}
+	}
+
+	if err := g.Write(buf, "pdf"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
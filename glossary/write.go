@@ -0,0 +1,128 @@
+package glossary
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formats are the supported output formats.
+var Formats = []string{"md", "csv", "html"}
+
+var header = []string{"word", "count", "level", "type", "translation"}
+
+func (e *Entry) row() []string {
+	return []string{
+		e.Word.Stressed.String(),
+		strconv.Itoa(e.Count),
+		e.Word.LanguageLevel.String(),
+		e.Word.WordType.Name(),
+		e.Translation(),
+	}
+}
+
+// Write writes the glossary in one of Formats.
+func (g *Glossary) Write(w io.Writer, format string) error {
+	switch format {
+	case "md":
+		return g.Markdown(w)
+	case "csv":
+		return g.CSV(w)
+	case "html":
+		return g.HTML(w)
+	}
+	return fmt.Errorf("unknown format '%s', available: %s", format, strings.Join(Formats, ", "))
+}
+
+var mdReplacer = strings.NewReplacer("|", "\\|", "\n", " ")
+
+func (g *Glossary) Markdown(w io.Writer) error {
+	var b strings.Builder
+	row := func(r []string) {
+		for i := range r {
+			r[i] = mdReplacer.Replace(r[i])
+		}
+		b.WriteString("| " + strings.Join(r, " | ") + " |\n")
+	}
+
+	row(append([]string{}, header...))
+	sep := make([]string, len(header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	sep[1] = "--:"
+	row(sep)
+	for _, e := range g.Entries {
+		row(e.row())
+	}
+	if len(g.Unknown) != 0 {
+		b.WriteString("\nUnknown: ")
+		b.WriteString(strings.Join(g.Unknown, ", "))
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// CSV writes the glossary without the unknown words.
+func (g *Glossary) CSV(w io.Writer) error {
+	c := csv.NewWriter(w)
+	if err := c.Write(header); err != nil {
+		return err
+	}
+	for _, e := range g.Entries {
+		if err := c.Write(e.row()); err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
+
+// HTML writes a standalone html page.
+func (g *Glossary) HTML(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Glossary</title>
+<style>
+	body  { font-family: sans-serif; }
+	table { border-collapse: collapse; }
+	th,td { text-align: left; padding: 4px 12px; border-bottom: 1px solid #ccc; }
+	.count { text-align: right; }
+	.unknown { color: #888; }
+</style>
+</head>
+<body>
+<table>
+<tr>`)
+	for _, h := range header {
+		fmt.Fprintf(&b, "<th>%s</th>", html.EscapeString(h))
+	}
+	b.WriteString("</tr>\n")
+	for _, e := range g.Entries {
+		b.WriteString("<tr>")
+		for i, v := range e.row() {
+			class := ""
+			if i == 1 {
+				class = ` class="count"`
+			}
+			fmt.Fprintf(&b, "<td%s>%s</td>", class, html.EscapeString(v))
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n")
+	if len(g.Unknown) != 0 {
+		fmt.Fprintf(&b, "<p class=\"unknown\">Unknown: %s</p>\n", html.EscapeString(strings.Join(g.Unknown, ", ")))
+	}
+	b.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
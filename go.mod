@@ -3,16 +3,21 @@ module github.com/frizinak/goru
 go 1.17
 
 require (
+	github.com/coreos/go-systemd v0.0.0-20191104093116-d3cd4ed1dbcf
 	github.com/frizinak/gotls v0.2.1
+	github.com/mattn/go-runewidth v0.0.3
+	github.com/mattn/go-sqlite3 v1.14.17
+	github.com/peterh/liner v1.2.2
 	github.com/tdewolff/minify/v2 v2.9.22
 	golang.org/x/image v0.0.0-20210628002857-a66eb6448b8d
+	golang.org/x/term v0.1.0
 )
 
 require (
-	github.com/coreos/go-systemd v0.0.0-20191104093116-d3cd4ed1dbcf // indirect
 	github.com/google/go-cmp v0.5.6 // indirect
 	github.com/tdewolff/parse/v2 v2.5.21 // indirect
 	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad // indirect
+	golang.org/x/sys v0.0.0-20211117180635-dee7805ff2e1 // indirect
 	golang.org/x/text v0.3.6 // indirect
 	gopkg.in/square/go-jose.v2 v2.5.1 // indirect
 )
@@ -0,0 +1,203 @@
+// Package history records lookups and derives statistics from them.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/openrussian"
+)
+
+// File returns the path of the history in dir.
+func File(dir string) string { return filepath.Join(dir, "lookups.jsonl") }
+
+type Entry struct {
+	Time  time.Time `json:"time"`
+	Query string    `json:"query"`
+	// ID of the first result, 0 if nothing was found.
+	ID openrussian.ID `json:"id,omitempty"`
+	// Fuzzy is true if the query did not match exactly.
+	Fuzzy bool `json:"fuzzy,omitempty"`
+}
+
+// NewEntry creates an entry for a lookup of query with the given results.
+func NewEntry(query string, fuzzy bool, words []*openrussian.Word) Entry {
+	e := Entry{Time: time.Now(), Query: query, Fuzzy: fuzzy}
+	if len(words) != 0 {
+		e.ID = words[0].ID
+	}
+	return e
+}
+
+// Recorder appends entries as json lines to a file.
+type Recorder struct {
+	l    sync.Mutex
+	path string
+}
+
+func NewRecorder(path string) *Recorder { return &Recorder{path: path} }
+
+func (r *Recorder) Record(e Entry) error {
+	d, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	d = append(d, '\n')
+
+	r.l.Lock()
+	defer r.l.Unlock()
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(d); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads all entries recorded at path. Malformed lines (e.g.: a partial
+// write) are skipped.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var l []Entry
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scan.Bytes(), &e); err == nil {
+			l = append(l, e)
+		}
+	}
+	return l, scan.Err()
+}
+
+// Since returns the entries recorded after t.
+func Since(entries []Entry, t time.Time) []Entry {
+	n := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if e.Time.After(t) {
+			n = append(n, e)
+		}
+	}
+	return n
+}
+
+type WordCount struct {
+	Word  *openrussian.Word
+	Count int
+	Last  time.Time
+}
+
+type Typo struct {
+	Word  *openrussian.Word
+	Query string
+	Count int
+	Edits dict.Edits
+}
+
+type Stats struct {
+	Lookups int
+	Fuzzy   int
+	// Words by amount of lookups.
+	Words []WordCount
+	// Typos are misspellings that were made more than once.
+	Typos []Typo
+	// Forgotten are words that were looked up on more than one day, most
+	// days first.
+	Forgotten []WordCount
+}
+
+func sortCounts(l []WordCount) {
+	sort.SliceStable(l, func(i, j int) bool {
+		if l[i].Count == l[j].Count {
+			return l[i].Last.After(l[j].Last)
+		}
+		return l[i].Count > l[j].Count
+	})
+}
+
+// NewStats aggregates entries, entries for words no longer in words are
+// ignored.
+func NewStats(entries []Entry, words openrussian.Words) Stats {
+	s := Stats{Lookups: len(entries)}
+	counts := make(map[openrussian.ID]*WordCount)
+	days := make(map[openrussian.ID]map[string]struct{})
+	typos := make(map[string]*Typo)
+	for _, e := range entries {
+		if e.Fuzzy {
+			s.Fuzzy++
+		}
+		w, ok := words[e.ID]
+		if !ok {
+			continue
+		}
+
+		c, ok := counts[e.ID]
+		if !ok {
+			c = &WordCount{Word: w}
+			counts[e.ID] = c
+			days[e.ID] = make(map[string]struct{})
+		}
+		c.Count++
+		if e.Time.After(c.Last) {
+			c.Last = e.Time
+		}
+		days[e.ID][e.Time.Local().Format("2006-01-02")] = struct{}{}
+
+		q := strings.ToLower(strings.TrimSpace(e.Query))
+		if !e.Fuzzy || !dict.IsCyrillic(q) || q == w.Lower {
+			continue
+		}
+		key := q + "\x00" + w.Lower
+		t, ok := typos[key]
+		if !ok {
+			t = &Typo{Word: w, Query: q, Edits: dict.LevenshteinEdits([]rune(w.Lower), []rune(q))}
+			typos[key] = t
+		}
+		t.Count++
+	}
+
+	for id, c := range counts {
+		s.Words = append(s.Words, *c)
+		if n := len(days[id]); n > 1 {
+			f := *c
+			f.Count = n
+			s.Forgotten = append(s.Forgotten, f)
+		}
+	}
+	sortCounts(s.Words)
+	sortCounts(s.Forgotten)
+
+	for _, t := range typos {
+		if t.Count > 1 {
+			s.Typos = append(s.Typos, *t)
+		}
+	}
+	sort.Slice(s.Typos, func(i, j int) bool {
+		if s.Typos[i].Count == s.Typos[j].Count {
+			return s.Typos[i].Query < s.Typos[j].Query
+		}
+		return s.Typos[i].Count > s.Typos[j].Count
+	})
+
+	return s
+}
@@ -0,0 +1,163 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+func day(d, h int) time.Time { return time.Date(2026, 1, d, h, 0, 0, 0, time.Local) }
+
+func TestRecordLoad(t *testing.T) {
+	path := File(filepath.Join(t.TempDir(), "goru"))
+	l, err := Load(path)
+	if err != nil || len(l) != 0 {
+		t.Fatalf("expected no entries for a missing file, got %v %v", l, err)
+	}
+
+	r := NewRecorder(path)
+	entries := []Entry{
+		{Time: day(1, 10), Query: "стол", ID: 3},
+		{Time: day(2, 10), Query: "qqq", Fuzzy: true},
+	}
+	for _, e := range entries {
+		if err := r.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2026-01-03T`)
+	f.Close()
+
+	l, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 2 {
+		t.Fatalf("expected 2 entries without the partial line, got %d", len(l))
+	}
+	for i := range l {
+		if !l[i].Time.Equal(entries[i].Time) || l[i].Query != entries[i].Query ||
+			l[i].ID != entries[i].ID || l[i].Fuzzy != entries[i].Fuzzy {
+			t.Errorf("round trip mismatch: %+v vs %+v", l[i], entries[i])
+		}
+	}
+
+	if n := Since(l, day(1, 12)); len(n) != 1 || n[0].Query != "qqq" {
+		t.Errorf("unexpected entries since day 1: %v", n)
+	}
+	if n := Since(l, day(2, 10)); len(n) != 0 {
+		t.Errorf("expected Since to exclude t itself, got %v", n)
+	}
+}
+
+func TestNewStats(t *testing.T) {
+	words := openrussian.Words{
+		1: {ID: 1, Word: "стол", Lower: "стол"},
+		2: {ID: 2, Word: "окно", Lower: "окно"},
+		3: {ID: 3, Word: "дом", Lower: "дом"},
+	}
+
+	type exp struct {
+		lookups, fuzzy int
+		words          string
+		typos          string
+		forgotten      string
+	}
+	tests := []struct {
+		name    string
+		entries []Entry
+		exp     exp
+	}{
+		{"empty", nil, exp{}},
+		{
+			"counts",
+			[]Entry{
+				{Time: day(1, 10), Query: "стол", ID: 1},
+				{Time: day(1, 11), Query: "стол", ID: 1},
+				{Time: day(1, 12), Query: "дом", ID: 3},
+				{Time: day(1, 13), Query: "gone", ID: 99},
+				{Time: day(1, 14), Query: "nothing", Fuzzy: true},
+			},
+			exp{lookups: 5, fuzzy: 1, words: "стол:2 дом:1"},
+		},
+		{
+			"ties by last lookup",
+			[]Entry{
+				{Time: day(1, 10), Query: "стол", ID: 1},
+				{Time: day(1, 12), Query: "дом", ID: 3},
+				{Time: day(1, 11), Query: "окно", ID: 2},
+			},
+			exp{lookups: 3, words: "дом:1 окно:1 стол:1"},
+		},
+		{
+			"typos made more than once",
+			[]Entry{
+				{Time: day(1, 10), Query: "стл", ID: 1, Fuzzy: true},
+				{Time: day(2, 10), Query: " Стл ", ID: 1, Fuzzy: true},
+				{Time: day(2, 11), Query: "акно", ID: 2, Fuzzy: true},
+				{Time: day(2, 12), Query: "table", ID: 1, Fuzzy: true},
+				{Time: day(2, 13), Query: "table", ID: 1, Fuzzy: true},
+				{Time: day(2, 14), Query: "стол", ID: 1, Fuzzy: true},
+				{Time: day(2, 15), Query: "стол", ID: 1, Fuzzy: true},
+			},
+			exp{
+				lookups:   7,
+				fuzzy:     7,
+				words:     "стол:6 окно:1",
+				typos:     "стл>стол:2",
+				forgotten: "стол:2",
+			},
+		},
+		{
+			"forgotten by days",
+			[]Entry{
+				{Time: day(1, 10), Query: "стол", ID: 1},
+				{Time: day(1, 11), Query: "стол", ID: 1},
+				{Time: day(1, 12), Query: "стол", ID: 1},
+				{Time: day(1, 10), Query: "дом", ID: 3},
+				{Time: day(2, 10), Query: "дом", ID: 3},
+				{Time: day(3, 10), Query: "дом", ID: 3},
+				{Time: day(1, 10), Query: "окно", ID: 2},
+				{Time: day(4, 10), Query: "окно", ID: 2},
+			},
+			exp{lookups: 8, words: "дом:3 стол:3 окно:2", forgotten: "дом:3 окно:2"},
+		},
+	}
+
+	counts := func(l []WordCount) string {
+		s := make([]string, len(l))
+		for i, c := range l {
+			s[i] = c.Word.Word + ":" + strconv.Itoa(c.Count)
+		}
+		return strings.Join(s, " ")
+	}
+	for _, test := range tests {
+		s := NewStats(test.entries, words)
+		typos := make([]string, len(s.Typos))
+		for i, ty := range s.Typos {
+			typos[i] = ty.Query + ">" + ty.Word.Word + ":" + strconv.Itoa(ty.Count)
+			if !ty.Edits.HasEdits() {
+				t.Errorf("%s: expected edits for %s", test.name, ty.Query)
+			}
+		}
+		got := exp{
+			lookups:   s.Lookups,
+			fuzzy:     s.Fuzzy,
+			words:     counts(s.Words),
+			typos:     strings.Join(typos, " "),
+			forgotten: counts(s.Forgotten),
+		}
+		if got != test.exp {
+			t.Errorf("%s:\nexp: %+v\ngot: %+v", test.name, test.exp, got)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package morph
+
+import "github.com/frizinak/goru/openrussian"
+
+// prefixes are common prefixes, longer ones are preferred when combining.
+var prefixes = []string{
+	"без", "бес", "в", "вз", "вс", "во", "воз", "вос", "вы", "до", "за", "из",
+	"изо", "ис", "на", "над", "недо", "низ", "нис", "о", "об", "обо", "от",
+	"ото", "пере", "по", "под", "подо", "пре", "пред", "при", "про", "раз",
+	"рас", "с", "со", "су", "у", "через",
+}
+
+// Endings of the dictionary forms, longest first.
+var (
+	verbEndings = []string{"ть", "ти", "чь"}
+	adjEndings  = []string{"ый", "ий", "ой", "ая", "яя", "ое", "ее", "ые", "ие"}
+	nounEndings = []string{"а", "я", "о", "е", "ы", "и"}
+)
+
+// suffixes are common suffixes per word type, longest first.
+var suffixes = map[openrussian.WordType][]string{
+	openrussian.Verb: {
+		"ова", "ева", "ыва", "ива", "ну", "и", "е", "а", "я",
+	},
+	openrussian.Noun: {
+		"тель", "ость", "есть", "ник", "щик", "чик", "ени", "ани", "ств",
+		"изм", "ист", "ниц", "ец", "иц", "ок", "ек", "ин", "к",
+	},
+	openrussian.Adjective: {
+		"альн", "енн", "янн", "онн", "ичн", "лив", "ист", "ан", "ян", "ов",
+		"ев", "ск", "н", "к",
+	},
+}
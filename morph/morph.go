@@ -0,0 +1,432 @@
+// Package morph splits russian words into prefixes, a root, suffixes, an
+// ending and a postfix (при-ход-и-ть).
+//
+// Segmentation is rule based: derivation links (Word.DerivedFrom, and verbs
+// for their participles) pin the root of a word to the root of the word it
+// was derived from, lists of common prefixes and suffixes split off the
+// rest. Root alternations (ход/хож) and compounds are not recognized.
+package morph
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+// Kind is the type of a morpheme.
+type Kind uint8
+
+const (
+	Prefix Kind = iota
+	Root
+	Suffix
+	Ending
+	Postfix
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Prefix:
+		return "prefix"
+	case Root:
+		return "root"
+	case Suffix:
+		return "suffix"
+	case Ending:
+		return "ending"
+	case Postfix:
+		return "postfix"
+	}
+	return "?"
+}
+
+// Morpheme is a part of a word.
+type Morpheme struct {
+	Kind Kind
+	Text string
+	// Stress is the index of the stressed rune in Text or -1.
+	Stress int
+}
+
+// Stressed returns Text with a stress mark if it contains the stressed
+// vowel.
+func (m Morpheme) Stressed() openrussian.Stressed {
+	if m.Stress < 0 {
+		return openrussian.Stressed(m.Text)
+	}
+	r := []rune(m.Text)
+	return openrussian.Stressed(string(r[:m.Stress+1]) + "'" + string(r[m.Stress+1:]))
+}
+
+// Segmentation is a word split into its morphemes.
+type Segmentation []Morpheme
+
+// String joins the morphemes with dashes.
+func (s Segmentation) String() string {
+	l := make([]string, len(s))
+	for i, m := range s {
+		l[i] = m.Text
+	}
+	return strings.Join(l, "-")
+}
+
+// Root returns the lowercase root or an empty string.
+func (s Segmentation) Root() string {
+	for _, m := range s {
+		if m.Kind == Root {
+			return strings.ToLower(m.Text)
+		}
+	}
+	return ""
+}
+
+// maxDepth limits how far DerivedFrom is followed.
+const maxDepth = 8
+
+// minRoot is the minimum length of a root that was not confirmed by a
+// derivation link.
+const minRoot = 3
+
+// Segmenter segments the words of a dictionary.
+type Segmenter struct {
+	l       sync.Mutex
+	words   openrussian.Words
+	cache   map[openrussian.ID]Segmentation
+	roots   map[string]struct{}
+	parents map[openrussian.ID]*openrussian.Word
+	index   map[string][]*openrussian.Word
+}
+
+// New creates a Segmenter for words, all indexes are built lazily.
+func New(words openrussian.Words) *Segmenter {
+	return &Segmenter{
+		words: words,
+		cache: make(map[openrussian.ID]Segmentation),
+	}
+}
+
+// Segment splits w into morphemes. Words of multiple parts (e.g.: phrases
+// and hyphenated words) are not segmented and return nil.
+func (s *Segmenter) Segment(w *openrussian.Word) Segmentation {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.initRoots()
+	seg, _ := s.segment(w, 0)
+	return seg
+}
+
+// WithRoot returns the words with the given root, sorted by rank (unranked
+// last) and then alphabetically.
+func (s *Segmenter) WithRoot(root string) []*openrussian.Word {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.initRoots()
+	if s.index == nil {
+		s.index = make(map[string][]*openrussian.Word)
+		for _, w := range s.words {
+			seg, _ := s.segment(w, 0)
+			if r := seg.Root(); r != "" {
+				s.index[r] = append(s.index[r], w)
+			}
+		}
+		for _, l := range s.index {
+			sortWords(l)
+		}
+	}
+	return s.index[strings.ToLower(openrussian.Stressed(root).Unstressed())]
+}
+
+func sortWords(l []*openrussian.Word) {
+	sort.Slice(l, func(i, j int) bool {
+		ri, rj := l[i].Rank, l[j].Rank
+		if ri != rj && (ri == 0 || rj == 0) {
+			return rj == 0
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		if l[i].Word != l[j].Word {
+			return l[i].Word < l[j].Word
+		}
+		return l[i].ID < l[j].ID
+	})
+}
+
+// initRoots collects the roots of underived words, found without stripping
+// prefixes. They allow stripping prefixes from other underived words.
+func (s *Segmenter) initRoots() {
+	if s.roots != nil {
+		return
+	}
+
+	s.parents = make(map[openrussian.ID]*openrussian.Word)
+	for _, w := range s.words {
+		if v := w.VerbInfo; v != nil {
+			for _, p := range []*openrussian.Word{v.ActivePresent, v.ActivePast, v.PassivePresent, v.PassivePast} {
+				if p != nil && p.DerivedFrom == nil {
+					s.parents[p.ID] = w
+				}
+			}
+		}
+	}
+
+	roots := make(map[string]struct{})
+	for _, w := range s.words {
+		if s.parent(w) != nil || !single(w.Word) {
+			continue
+		}
+		word := []rune(strings.ToLower(w.Word))
+		_, stem := inflection(w.WordType, word)
+		if r := s.stripSuffixes(w.WordType, word[:stem]); r >= minRoot {
+			roots[string(word[:r])] = struct{}{}
+		}
+	}
+	s.roots = roots
+}
+
+// parent returns the word w was derived from, participles are derived from
+// their verb.
+func (s *Segmenter) parent(w *openrussian.Word) *openrussian.Word {
+	if w.DerivedFrom != nil {
+		return w.DerivedFrom
+	}
+	return s.parents[w.ID]
+}
+
+func single(word string) bool {
+	return word != "" && !strings.ContainsAny(word, " -")
+}
+
+type span struct {
+	kind       Kind
+	start, end int
+}
+
+// segment segments w, complete is false if the derivation links were not
+// followed to the end because of maxDepth. Only complete segmentations are
+// cached, a word first reached deep in a chain is segmented again when
+// looked up directly.
+func (s *Segmenter) segment(w *openrussian.Word, depth int) (seg Segmentation, complete bool) {
+	if seg, ok := s.cache[w.ID]; ok {
+		return seg, true
+	}
+	if !single(w.Word) {
+		s.cache[w.ID] = nil
+		return nil, true
+	}
+
+	word := []rune(strings.ToLower(w.Word))
+	spans, complete := s.split(w, word, depth)
+
+	stress := -1
+	if p := w.Stressed.Parse(); len(p) == 1 && p[0].Stress != "" {
+		stress = len([]rune(p[0].Prefix))
+	}
+
+	orig := []rune(w.Word)
+	seg = make(Segmentation, 0, len(spans))
+	for _, sp := range spans {
+		m := Morpheme{Kind: sp.kind, Text: string(orig[sp.start:sp.end]), Stress: -1}
+		if stress >= sp.start && stress < sp.end {
+			m.Stress = stress - sp.start
+		}
+		seg = append(seg, m)
+	}
+	if complete {
+		s.cache[w.ID] = seg
+	}
+	return seg, complete
+}
+
+func (s *Segmenter) split(w *openrussian.Word, word []rune, depth int) ([]span, bool) {
+	end, stem := inflection(w.WordType, word)
+	tail := make([]span, 0, 2)
+	if stem != end {
+		tail = append(tail, span{Ending, stem, end})
+	}
+	if end != len(word) {
+		tail = append(tail, span{Postfix, end, len(word)})
+	}
+
+	spans := make([]span, 0, 6)
+	complete := true
+	if p := s.parent(w); p != nil {
+		complete = false
+		if depth < maxDepth {
+			var seg Segmentation
+			seg, complete = s.segment(p, depth+1)
+			if root := seg.Root(); len([]rune(root)) >= 2 {
+				if sp, ok := derived(w.WordType, word[:stem], []rune(root)); ok {
+					return append(append(spans, sp...), tail...), complete
+				}
+			}
+		}
+	}
+
+	start := 0
+	for _, p := range prefixesOf(word[:stem]) {
+		rest := word[p:stem]
+		r := s.stripSuffixes(w.WordType, rest)
+		if _, ok := s.roots[string(rest[:r])]; ok && r >= minRoot {
+			start = p
+			break
+		}
+	}
+	if start != 0 {
+		spans = appendPrefixes(spans, word[:start], 0)
+	}
+
+	r := start + s.stripSuffixes(w.WordType, word[start:stem])
+	spans = append(spans, span{Root, start, r})
+	spans = appendSuffixes(spans, w.WordType, word[:stem], r, false)
+	return append(spans, tail...), complete
+}
+
+// derived segments a stem that contains the root of the word it was derived
+// from.
+func derived(typ openrussian.WordType, stem, root []rune) ([]span, bool) {
+	ix := index(stem, root)
+	if ix < 0 {
+		return nil, false
+	}
+	spans := make([]span, 0, 6)
+	if ix != 0 {
+		spans = appendPrefixes(spans, stem[:ix], 0)
+	}
+	r := ix + len(root)
+	spans = append(spans, span{Root, ix, r})
+	return appendSuffixes(spans, typ, stem, r, true), true
+}
+
+func index(s, sub []rune) int {
+	if ix := strings.Index(string(s), string(sub)); ix >= 0 {
+		return len([]rune(string(s)[:ix]))
+	}
+	return -1
+}
+
+// inflection returns the end of the stem and the start of the ending in
+// word. Everything after end is a postfix.
+func inflection(typ openrussian.WordType, word []rune) (end, stem int) {
+	end = len(word)
+	if typ == openrussian.Verb && end > 4 && hasSuffix(word, "ся", "сь") != "" {
+		end -= 2
+	}
+
+	stem = end
+	var endings []string
+	switch typ {
+	case openrussian.Verb:
+		endings = verbEndings
+	case openrussian.Adjective:
+		endings = adjEndings
+	case openrussian.Noun:
+		endings = nounEndings
+	}
+	if e := hasSuffix(word[:end], endings...); e != "" && end-len([]rune(e)) >= 2 {
+		stem -= len([]rune(e))
+	}
+	return
+}
+
+func hasSuffix(word []rune, list ...string) string {
+	s := string(word)
+	for _, e := range list {
+		if strings.HasSuffix(s, e) {
+			return e
+		}
+	}
+	return ""
+}
+
+// prefixesOf returns the possible ends of the prefixes of stem, longest
+// first. At most two prefixes are combined.
+func prefixesOf(stem []rune) []int {
+	var ends []int
+	for _, p := range prefixes {
+		pr := []rune(p)
+		if !hasPrefix(stem, pr) {
+			continue
+		}
+		ends = append(ends, len(pr))
+		for _, p2 := range prefixes {
+			if pr2 := []rune(p2); hasPrefix(stem[len(pr):], pr2) {
+				ends = append(ends, len(pr)+len(pr2))
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ends)))
+	return ends
+}
+
+func hasPrefix(s, prefix []rune) bool {
+	return strings.HasPrefix(string(s), string(prefix))
+}
+
+// appendPrefixes splits pre into known prefixes, anything left is a single
+// prefix.
+func appendPrefixes(spans []span, pre []rune, offset int) []span {
+	for len(pre) != 0 {
+		n := 0
+		for _, p := range prefixes {
+			pr := []rune(p)
+			if len(pr) > n && hasPrefix(pre, pr) {
+				n = len(pr)
+			}
+		}
+		if n == 0 {
+			n = len(pre)
+		}
+		spans = append(spans, span{Prefix, offset, offset + n})
+		pre, offset = pre[n:], offset+n
+	}
+	return spans
+}
+
+// stripSuffixes returns the length of stem without up to two known
+// suffixes, keeping at least minRoot runes. Short suffixes of words other
+// than verbs are only stripped if what remains is a known root (замок is not
+// зам-ок).
+func (s *Segmenter) stripSuffixes(typ openrussian.WordType, stem []rune) int {
+	n := len(stem)
+	for i := 0; i < 2; i++ {
+		sf := []rune(hasSuffix(stem[:n], suffixes[typ]...))
+		if len(sf) == 0 || n-len(sf) < minRoot+i {
+			break
+		}
+		if _, ok := s.roots[string(stem[:n-len(sf)])]; !ok && typ != openrussian.Verb && len(sf) < 3 {
+			break
+		}
+		n -= len(sf)
+	}
+	return n
+}
+
+// appendSuffixes splits stem[from:] into known suffixes. Unknown parts become
+// a single suffix when confirmed by a derivation link, otherwise they are
+// added to the root.
+func appendSuffixes(spans []span, typ openrussian.WordType, stem []rune, from int, confirmed bool) []span {
+	var rev []span
+	end := len(stem)
+	for end > from {
+		sf := hasSuffix(stem[from:end], suffixes[typ]...)
+		if sf == "" {
+			break
+		}
+		rev = append(rev, span{Suffix, end - len([]rune(sf)), end})
+		end -= len([]rune(sf))
+	}
+	if end > from {
+		if confirmed {
+			spans = append(spans, span{Suffix, from, end})
+		} else {
+			spans[len(spans)-1].end = end
+		}
+	}
+	for i := len(rev) - 1; i >= 0; i-- {
+		spans = append(spans, rev[i])
+	}
+	return spans
+}
@@ -0,0 +1,99 @@
+package morph
+
+import (
+	"testing"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+func TestSegment(t *testing.T) {
+	words := make(openrussian.Words)
+	add := func(id openrussian.ID, stressed string, typ openrussian.WordType, from openrussian.ID) *openrussian.Word {
+		w := &openrussian.Word{
+			ID:          id,
+			Word:        openrussian.Stressed(stressed).Unstressed(),
+			Stressed:    openrussian.Stressed(stressed),
+			WordType:    typ,
+			DerivedFrom: words[from],
+		}
+		words[id] = w
+		return w
+	}
+	add(1, "ходи'ть", openrussian.Verb, 0)
+	add(2, "приходи'ть", openrussian.Verb, 1)
+	add(3, "выходи'ть", openrussian.Verb, 0)
+	add(4, "писа'ть", openrussian.Verb, 0)
+	add(5, "писа'тель", openrussian.Noun, 4)
+	add(6, "но'вый", openrussian.Adjective, 0)
+	add(7, "за'мок", openrussian.Noun, 0)
+	add(8, "купа'ться", openrussian.Verb, 0)
+	add(9, "де'лать", openrussian.Verb, 0)
+	part := add(10, "де'лающий", openrussian.Adjective, 0)
+	add(11, "до'брый вечер", openrussian.Expression, 0)
+	words[9].VerbInfo = &openrussian.VerbInfo{ActivePresent: part}
+
+	s := New(words)
+	tests := map[openrussian.ID]string{
+		1:  "ход-и-ть",
+		2:  "при-ход-и-ть",
+		3:  "вы-ход-и-ть",
+		5:  "пис-а-тель",
+		6:  "нов-ый",
+		7:  "замок",
+		8:  "куп-а-ть-ся",
+		10: "дел-ающ-ий",
+		11: "",
+	}
+	for id, exp := range tests {
+		if got := s.Segment(words[id]).String(); got != exp {
+			t.Errorf("%s: expected %s got %s", words[id].Word, exp, got)
+		}
+	}
+
+	seg := s.Segment(words[2])
+	if seg[3].Kind != Ending || seg[2].Kind != Suffix || seg[0].Kind != Prefix {
+		t.Errorf("unexpected kinds: %v", seg)
+	}
+	if got := seg[2].Stressed().String(); got != "и́" {
+		t.Errorf("expected stress on и, got %s", got)
+	}
+	if seg[1].Stress != -1 {
+		t.Errorf("expected an unstressed root, got %d", seg[1].Stress)
+	}
+
+	l := s.WithRoot("ход")
+	if len(l) != 3 {
+		t.Errorf("expected 3 words with root ход, got %d", len(l))
+	}
+}
+
+func TestSegmentOrder(t *testing.T) {
+	words := make(openrussian.Words)
+	chain := make([]*openrussian.Word, maxDepth+3)
+	for i := range chain {
+		w := &openrussian.Word{ID: openrussian.ID(i + 1), WordType: openrussian.Noun}
+		w.Word, w.Stressed = "писатель", "писа'тель"
+		if i == 0 {
+			w.Word, w.Stressed, w.WordType = "писать", "писа'ть", openrussian.Verb
+		} else {
+			w.DerivedFrom = chain[i-1]
+		}
+		chain[i], words[w.ID] = w, w
+	}
+
+	// Segment the end of the chain first, its ancestors are reached at
+	// maxDepth and segmented without their own parent.
+	deep := New(words)
+	deep.Segment(chain[len(chain)-1])
+
+	fresh := New(words)
+	for _, w := range chain[1:] {
+		exp := fresh.Segment(w).String()
+		if exp != "пис-а-тель" {
+			t.Fatalf("%d: expected пис-а-тель got %s", w.ID, exp)
+		}
+		if got := deep.Segment(w).String(); got != exp {
+			t.Errorf("%d: expected %s got %s", w.ID, exp, got)
+		}
+	}
+}
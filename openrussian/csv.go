@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -48,6 +49,8 @@ func parseUint64(d string, optional bool) (uint64, error) {
 	return strconv.ParseUint(d, 10, 64)
 }
 
+var usageColumns = map[string]int{"en": 8, "de": 9}
+
 func DecodeWords(r io.Reader) (CSVWords, error) {
 	words := make(CSVWords, 10000)
 	err := dec(r, func(n int, row []string) error {
@@ -93,7 +96,14 @@ func DecodeWords(r io.Reader) (CSVWords, error) {
 		}
 		w.DerivedFrom = ID(deriv)
 		w.Rank = rank
-		w.Usage = row[8]
+		for lang, ix := range usageColumns {
+			if u := strings.TrimSpace(row[ix]); u != "" {
+				if w.Usage == nil {
+					w.Usage = make(map[string]string, len(usageColumns))
+				}
+				w.Usage[lang] = u
+			}
+		}
 		w.WordType = wordType(row[11])
 		w.LanguageLevel = languageLevel(row[12])
 
@@ -121,10 +131,6 @@ func DecodeTranslations(r io.Reader) (CSVTranslations, error) {
 			row = r
 		}
 
-		if row[1] != "en" {
-			return nil
-		}
-
 		t := CSVTranslation{}
 
 		id, err := parseUint64(row[0], false)
@@ -137,6 +143,7 @@ func DecodeTranslations(r io.Reader) (CSVTranslations, error) {
 		}
 
 		t.ID = ID(id)
+		t.Lang = row[1]
 		t.Word = ID(word)
 		t.Translation = row[4]
 		t.Example = row[5]
@@ -390,6 +397,130 @@ func DecodeConjugations(r io.Reader) (CSVConjugations, error) {
 	return conjs, err
 }
 
+func DecodeSentences(r io.Reader) (CSVSentences, error) {
+	sentences := make(CSVSentences, 10000)
+	err := dec(r, func(n int, row []string) error {
+		if n == 1 {
+			return nil
+		}
+
+		if len(row) != 5 {
+			r := make([]string, 5)
+			copy(r, row)
+			row = r
+		}
+
+		if row[3] == "1" {
+			return nil
+		}
+
+		s := CSVSentence{}
+
+		id, err := parseUint64(row[0], false)
+		if err != nil {
+			return err
+		}
+
+		s.ID = ID(id)
+		s.Russian = Stressed(strings.TrimSpace(row[1]))
+		s.LanguageLevel = languageLevel(row[2])
+
+		if _, ok := sentences[s.ID]; ok {
+			return fmt.Errorf("duplicate sentence on line %d: id: %d", n, s.ID)
+		}
+		sentences[s.ID] = s
+		return nil
+	})
+
+	return sentences, err
+}
+
+func DecodeSentenceTranslations(r io.Reader) (CSVSentenceTranslations, error) {
+	trans := make(CSVSentenceTranslations, 10000)
+	err := dec(r, func(n int, row []string) error {
+		if n == 1 {
+			return nil
+		}
+
+		if len(row) != 4 {
+			r := make([]string, 4)
+			copy(r, row)
+			row = r
+		}
+
+		t := CSVSentenceTranslation{}
+
+		id, err := parseUint64(row[0], false)
+		if err != nil {
+			return err
+		}
+		sentence, err := parseUint64(row[2], false)
+		if err != nil {
+			return err
+		}
+
+		t.ID = ID(id)
+		t.Lang = row[1]
+		t.Sentence = ID(sentence)
+		t.Translation = strings.TrimSpace(row[3])
+
+		if _, ok := trans[t.ID]; ok {
+			return fmt.Errorf("duplicate sentence translation on line %d: id: %d", n, t.ID)
+		}
+		trans[t.ID] = t
+		return nil
+	})
+
+	return trans, err
+}
+
+func DecodeSentenceWords(r io.Reader) (CSVSentenceWords, error) {
+	links := make(CSVSentenceWords, 10000)
+	err := dec(r, func(n int, row []string) error {
+		if n == 1 {
+			return nil
+		}
+
+		if len(row) != 5 {
+			r := make([]string, 5)
+			copy(r, row)
+			row = r
+		}
+
+		l := CSVSentenceWord{}
+
+		id, err := parseUint64(row[0], false)
+		if err != nil {
+			return err
+		}
+		sentence, err := parseUint64(row[1], false)
+		if err != nil {
+			return err
+		}
+		word, err := parseUint64(row[2], false)
+		if err != nil {
+			return err
+		}
+		pos, err := parseUint64(row[3], true)
+		if err != nil {
+			return err
+		}
+
+		l.ID = ID(id)
+		l.Sentence = ID(sentence)
+		l.Word = ID(word)
+		l.Position = pos
+
+		if _, ok := links[l.ID]; ok {
+			return fmt.Errorf("duplicate sentence word on line %d: id: %d", n, l.ID)
+		}
+		links[l.ID] = l
+		return nil
+	})
+
+	return links, err
+}
+
 func Merge(
 	cw CSVWords,
 	ct CSVTranslations,
@@ -430,14 +561,17 @@ func Merge(
 				Gender:       n.Gender,
 				SingularOnly: n.SingularOnly,
 				PluralOnly:   n.PluralOnly,
+				Singular:     decls[n.DeclinationSingular],
+				Plural:       decls[n.DeclinationPlural],
 			}
 		}
 
 		var adj *AdjInfo
 		if a, ok := ca[i]; ok {
 			adj = &AdjInfo{
-				Comparative: a.Comparative,
-				Superlative: a.Superlative,
+				Incomparable: a.Incomparable,
+				Comparative:  a.Comparative,
+				Superlative:  a.Superlative,
 			}
 
 			if len(a.ShortM) != 0 || decls[a.DeclM] != nil {
@@ -470,10 +604,13 @@ func Merge(
 
 		words[i] = &Word{
 			ID:            w.ID,
+			Position:      w.Position,
 			Rank:          w.Rank,
 			Word:          w.Word,
 			Lower:         strings.ToLower(w.Word),
 			Stressed:      w.Stressed,
+			Usage:         w.Usage[DefaultLanguage],
+			Usages:        w.Usage,
 			Translations:  make([]*Translation, 0, 1),
 			WordType:      w.WordType,
 			LanguageLevel: w.LanguageLevel,
@@ -501,6 +638,7 @@ func Merge(
 		words[t.Word].Translations = append(
 			words[t.Word].Translations,
 			&Translation{
+				Lang:               t.Lang,
 				Translation:        t.Translation,
 				Example:            t.Example,
 				ExampleTranslation: t.ExampleTranslation,
@@ -511,3 +649,57 @@ func Merge(
 
 	return words
 }
+
+// MergeSentences attaches the given sentences to the words they contain.
+func MergeSentences(
+	words Words,
+	cs CSVSentences,
+	ct CSVSentenceTranslations,
+	cw CSVSentenceWords,
+) Sentences {
+	sentences := make(Sentences, len(cs))
+	for _, s := range cs {
+		sentences[s.ID] = &Sentence{
+			ID:            s.ID,
+			Russian:       s.Russian,
+			LanguageLevel: s.LanguageLevel,
+			Translations:  make([]*SentenceTranslation, 0, 1),
+		}
+	}
+
+	for _, t := range ct {
+		s, ok := sentences[t.Sentence]
+		if !ok {
+			continue
+		}
+		s.Translations = append(
+			s.Translations,
+			&SentenceTranslation{Lang: t.Lang, Translation: t.Translation},
+		)
+	}
+
+	links := make([]CSVSentenceWord, 0, len(cw))
+	for _, l := range cw {
+		links = append(links, l)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Position == links[j].Position {
+			return links[i].ID < links[j].ID
+		}
+		return links[i].Position < links[j].Position
+	})
+
+	for _, l := range links {
+		s, ok := sentences[l.Sentence]
+		if !ok {
+			continue
+		}
+		if _, ok := words[l.Word]; !ok {
+			continue
+		}
+		s.WordIDs = append(s.WordIDs, l.Word)
+	}
+
+	sentences.Link(words)
+	return sentences
+}
@@ -216,6 +216,12 @@ var allLanguageLevelsRev = map[LanguageLevel]string{
 	C2: "C2",
 }
 
+// ParseLanguageLevel parses a CEFR level, e.g.: A1.
+func ParseLanguageLevel(s string) (LanguageLevel, bool) {
+	l := languageLevel(s)
+	return l, l != 0
+}
+
 func languageLevel(s string) LanguageLevel {
 	s = strings.ToUpper(s)
 	if v, ok := allLanguageLevels[s]; ok {
@@ -255,6 +261,22 @@ var allWordTypesRev = map[WordType]string{
 	Verb:       "verb",
 }
 
+// Name returns the unabbreviated name of the word type.
+func (w WordType) Name() string {
+	for n, t := range allWordTypes {
+		if t == w {
+			return n
+		}
+	}
+	return ""
+}
+
+// ParseWordType parses an unabbreviated word type, e.g.: noun.
+func ParseWordType(s string) (WordType, bool) {
+	t, ok := allWordTypes[strings.ToLower(s)]
+	return t, ok
+}
+
 func wordType(s string) WordType {
 	s = strings.ToLower(s)
 	if v, ok := allWordTypes[s]; ok {
@@ -272,6 +294,17 @@ type Declension struct {
 	Prep StressedList
 }
 
+func (d *Declension) Forms() StressedList {
+	if d == nil {
+		return nil
+	}
+	l := make(StressedList, 0, 6)
+	for _, c := range []StressedList{d.Nom, d.Gen, d.Dat, d.Acc, d.Inst, d.Prep} {
+		l = append(l, c...)
+	}
+	return l
+}
+
 type AdjGenderInfo struct {
 	Gender Gender
 	Short  StressedList
@@ -279,8 +312,9 @@ type AdjGenderInfo struct {
 }
 
 type AdjInfo struct {
-	Comparative StressedList
-	Superlative StressedList
+	Incomparable bool
+	Comparative  StressedList
+	Superlative  StressedList
 
 	F, M, N, Pl *AdjGenderInfo
 }
@@ -289,12 +323,21 @@ type NounInfo struct {
 	Gender       Gender
 	SingularOnly bool
 	PluralOnly   bool
+	Singular     *Declension
+	Plural       *Declension
 }
 
 type Conjugation struct {
 	Sg1, Sg2, Sg3, Pl1, Pl2, Pl3 Stressed
 }
 
+func (c *Conjugation) Forms() StressedList {
+	if c == nil {
+		return nil
+	}
+	return StressedList{c.Sg1, c.Sg2, c.Sg3, c.Pl1, c.Pl2, c.Pl3}
+}
+
 type VerbInfo struct {
 	Aspect Aspect
 
@@ -316,10 +359,13 @@ type Words map[ID]*Word
 
 type Word struct {
 	ID            ID
+	Position      uint64
 	Rank          uint64
 	Word          string
 	Lower         string
 	Stressed      Stressed
+	Usage         string
+	Usages        map[string]string
 	DerivedFrom   *Word
 	Translations  []*Translation
 	WordType      WordType
@@ -327,12 +373,19 @@ type Word struct {
 	NounInfo      *NounInfo
 	AdjInfo       *AdjInfo
 	VerbInfo      *VerbInfo
+
+	sentences []*Sentence
+	// lang is the language of a Localized copy.
+	lang string
 }
 
-func (w *Word) HasTranslation(qry string) (bool, int) {
+func (w *Word) HasTranslation(lang, qry string) (bool, int) {
 	smallest := 10000
 	found := false
 	for _, t := range w.Translations {
+		if t.Lang != lang {
+			continue
+		}
 		if f, v := t.HasTranslation(qry); f && v < smallest {
 			found = true
 			smallest = v
@@ -342,6 +395,69 @@ func (w *Word) HasTranslation(qry string) (bool, int) {
 	return found, smallest
 }
 
+// Forms returns the word itself followed by all of its known inflected forms.
+func (w *Word) Forms() StressedList {
+	l := StressedList{w.Stressed}
+	if n := w.NounInfo; n != nil {
+		l = append(l, n.Singular.Forms()...)
+		l = append(l, n.Plural.Forms()...)
+	}
+	if a := w.AdjInfo; a != nil {
+		l = append(l, a.Comparative...)
+		l = append(l, a.Superlative...)
+		for _, g := range []*AdjGenderInfo{a.M, a.F, a.N, a.Pl} {
+			if g == nil {
+				continue
+			}
+			l = append(l, g.Short...)
+			l = append(l, g.Decl.Forms()...)
+		}
+	}
+	if v := w.VerbInfo; v != nil {
+		l = append(l, v.Conjugation.Forms()...)
+		l = append(
+			l,
+			v.ImperativeSg, v.ImperativePl,
+			v.PastM, v.PastF, v.PastN, v.PastPl,
+		)
+	}
+
+	seen := make(map[Stressed]struct{}, len(l))
+	forms := make(StressedList, 0, len(l))
+	for _, f := range l {
+		if f == "" {
+			continue
+		}
+		if _, ok := seen[f]; ok {
+			continue
+		}
+		seen[f] = struct{}{}
+		forms = append(forms, f)
+	}
+	return forms
+}
+
+func (w *Word) TranslationsFor(lang string) []*Translation {
+	l := make([]*Translation, 0, len(w.Translations))
+	for _, t := range w.Translations {
+		if t.Lang == lang {
+			l = append(l, t)
+		}
+	}
+	return l
+}
+
+// Localized returns a shallow copy of w that only holds the translations
+// and usage note for the given language. Its Sentences are localized when
+// requested.
+func (w *Word) Localized(lang string) *Word {
+	c := *w
+	c.Usage = w.Usages[lang]
+	c.Translations = w.TranslationsFor(lang)
+	c.lang = lang
+	return &c
+}
+
 func (w *Word) String() string {
 	if w.DerivedFrom == nil {
 		return fmt.Sprintf("%s %s", w.Stressed, w.WordType)
@@ -350,7 +466,10 @@ func (w *Word) String() string {
 	return fmt.Sprintf("%s %s [%s]", w.Stressed, w.WordType, w.DerivedFrom.Stressed)
 }
 
+const DefaultLanguage = "en"
+
 type Translation struct {
+	Lang               string
 	Translation        string
 	Example            string
 	ExampleTranslation string
@@ -397,6 +516,6 @@ func (t *Translation) HasTranslation(qry string) (bool, int) {
 	return ok, n
 }
 
-func (t Translation) String() string {
+func (t *Translation) String() string {
 	return t.Translation
 }
@@ -9,7 +9,7 @@ type CSVWord struct {
 	Stressed      Stressed
 	DerivedFrom   ID
 	Rank          uint64
-	Usage         string
+	Usage         map[string]string
 	WordType      WordType
 	LanguageLevel LanguageLevel
 }
@@ -49,6 +49,7 @@ type CSVTranslations map[ID]CSVTranslation
 
 type CSVTranslation struct {
 	ID                 ID
+	Lang               string
 	Word               ID
 	Translation        string
 	Example            string
@@ -93,3 +94,29 @@ type CSVConjugation struct {
 	ID                           ID
 	Sg1, Sg2, Sg3, Pl1, Pl2, Pl3 Stressed
 }
+
+type CSVSentences map[ID]CSVSentence
+
+type CSVSentence struct {
+	ID            ID
+	Russian       Stressed
+	LanguageLevel LanguageLevel
+}
+
+type CSVSentenceTranslations map[ID]CSVSentenceTranslation
+
+type CSVSentenceTranslation struct {
+	ID          ID
+	Lang        string
+	Sentence    ID
+	Translation string
+}
+
+type CSVSentenceWords map[ID]CSVSentenceWord
+
+type CSVSentenceWord struct {
+	ID       ID
+	Sentence ID
+	Word     ID
+	Position uint64
+}
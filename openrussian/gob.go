@@ -10,15 +10,29 @@ import (
 
 func init() {
 	gob.Register(Words{})
+	gob.Register(Sentences{})
 }
 
 func EncodeGOB(w io.Writer, words Words) error {
-	return gob.NewEncoder(w).Encode(words)
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(words); err != nil {
+		return err
+	}
+	return enc.Encode(words.Sentences())
 }
 
 func DecodeGOB(r io.Reader) (Words, error) {
 	var w Words
-	return w, gob.NewDecoder(r).Decode(&w)
+	var s Sentences
+	dec := gob.NewDecoder(r)
+	if err := dec.Decode(&w); err != nil {
+		return w, err
+	}
+	if err := dec.Decode(&s); err != nil && err != io.EOF {
+		return w, err
+	}
+	s.Link(w)
+	return w, nil
 }
 
 func StoreGOB(file string, words Words) error {
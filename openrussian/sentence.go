@@ -0,0 +1,102 @@
+package openrussian
+
+import "sort"
+
+type Sentences map[ID]*Sentence
+
+type Sentence struct {
+	ID            ID
+	Russian       Stressed
+	LanguageLevel LanguageLevel
+	Translations  []*SentenceTranslation
+	WordIDs       []ID
+
+	words []*Word
+}
+
+type SentenceTranslation struct {
+	Lang        string
+	Translation string
+}
+
+func (s SentenceTranslation) String() string { return s.Translation }
+
+// Words returns the words used in this sentence, in order of appearance.
+func (s *Sentence) Words() []*Word { return s.words }
+
+func (s *Sentence) TranslationsFor(lang string) []*SentenceTranslation {
+	l := make([]*SentenceTranslation, 0, len(s.Translations))
+	for _, t := range s.Translations {
+		if t.Lang == lang {
+			l = append(l, t)
+		}
+	}
+	return l
+}
+
+func (s *Sentence) Localized(lang string) *Sentence {
+	c := *s
+	c.Translations = s.TranslationsFor(lang)
+	return &c
+}
+
+func (s *Sentence) String() string { return s.Russian.String() }
+
+// Sentences returns the example sentences this word appears in, only holding
+// the translations for its language if w was Localized.
+func (w *Word) Sentences() []*Sentence {
+	if w.lang == "" {
+		return w.sentences
+	}
+	l := make([]*Sentence, len(w.sentences))
+	for i, s := range w.sentences {
+		l[i] = s.Localized(w.lang)
+	}
+	return l
+}
+
+// Link resolves the WordIDs of each sentence and attaches the sentences
+// to the words they contain.
+func (s Sentences) Link(words Words) {
+	for _, w := range words {
+		w.sentences = nil
+	}
+
+	for _, sentence := range s {
+		sentence.words = make([]*Word, 0, len(sentence.WordIDs))
+		seen := make(map[ID]struct{}, len(sentence.WordIDs))
+		for _, id := range sentence.WordIDs {
+			w, ok := words[id]
+			if !ok {
+				continue
+			}
+			sentence.words = append(sentence.words, w)
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			w.sentences = append(w.sentences, sentence)
+		}
+	}
+
+	for _, w := range words {
+		sort.Slice(w.sentences, func(i, j int) bool {
+			a, b := w.sentences[i], w.sentences[j]
+			if a.LanguageLevel == b.LanguageLevel {
+				return a.ID < b.ID
+			}
+			return a.LanguageLevel < b.LanguageLevel
+		})
+	}
+}
+
+// Sentences collects all sentences attached to the given words.
+func (w Words) Sentences() Sentences {
+	s := make(Sentences)
+	for _, word := range w {
+		for _, sentence := range word.sentences {
+			s[sentence.ID] = sentence
+		}
+	}
+	return s
+}
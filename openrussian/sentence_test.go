@@ -0,0 +1,107 @@
+package openrussian
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	testSentences = "id\tru\tlevel\tdisabled\tlocked\n" +
+		"1\tЯ пишу́.\tA2\t0\t0\n" +
+		"2\tСтол но́вый.\tA1\t\t\n" +
+		"3\tdisabled\tA1\t1\t0\n"
+	testSentenceTranslations = "id\tlang\tsentence_id\ttl\n" +
+		"1\ten\t1\tI write.\n" +
+		"2\tde\t1\tIch schreibe.\n" +
+		"3\ten\t2\tThe table is new.\n" +
+		"4\ten\t3\tdisabled\n"
+	testSentenceWords = "id\tsentence_id\tword_id\tposition\tform\n" +
+		"1\t1\t20\t2\tпишу\n" +
+		"2\t1\t10\t1\tя\n" +
+		"3\t1\t99\t3\tunknown\n" +
+		"4\t2\t30\t1\tстол\n" +
+		"5\t2\t20\t\t\n"
+)
+
+func testMergeSentences(t *testing.T) (Words, Sentences) {
+	t.Helper()
+	cs, err := DecodeSentences(strings.NewReader(testSentences))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := DecodeSentenceTranslations(strings.NewReader(testSentenceTranslations))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cw, err := DecodeSentenceWords(strings.NewReader(testSentenceWords))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	words := Words{
+		10: {ID: 10, Word: "я"},
+		20: {ID: 20, Word: "писать"},
+		30: {ID: 30, Word: "стол"},
+	}
+	return words, MergeSentences(words, cs, ct, cw)
+}
+
+func TestDecodeSentences(t *testing.T) {
+	cs, err := DecodeSentences(strings.NewReader(testSentences))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs) != 2 {
+		t.Fatalf("expected 2 sentences without the disabled one, got %d", len(cs))
+	}
+	if s := cs[1]; s.Russian != "Я пишу́." || s.LanguageLevel != A2 {
+		t.Errorf("unexpected sentence: %+v", s)
+	}
+
+	_, err = DecodeSentences(strings.NewReader(testSentences + "1\tdup\tA1\t0\t0\n"))
+	if err == nil {
+		t.Error("expected an error for a duplicate id")
+	}
+	_, err = DecodeSentenceWords(strings.NewReader("header\nx\t1\t1\t1\t\n"))
+	if err == nil {
+		t.Error("expected an error for an invalid id")
+	}
+}
+
+func TestMergeSentences(t *testing.T) {
+	words, sentences := testMergeSentences(t)
+	if len(sentences) != 2 {
+		t.Fatalf("expected 2 sentences, got %d", len(sentences))
+	}
+
+	s := sentences[1]
+	if len(s.Translations) != 2 {
+		t.Errorf("expected 2 translations, got %d", len(s.Translations))
+	}
+	if len(s.WordIDs) != 2 || s.WordIDs[0] != 10 || s.WordIDs[1] != 20 {
+		t.Errorf("expected the known words in order of position, got %v", s.WordIDs)
+	}
+	if w := s.Words(); len(w) != 2 || w[0] != words[10] {
+		t.Errorf("expected linked words, got %v", w)
+	}
+
+	l := words[20].Sentences()
+	if len(l) != 2 || l[0].ID != 2 || l[1].ID != 1 {
+		t.Errorf("expected sentences sorted by level, got %v", l)
+	}
+	if l := words[30].Sentences(); len(l) != 1 || l[0].ID != 2 {
+		t.Errorf("unexpected sentences for стол: %v", l)
+	}
+}
+
+func TestLocalizedSentences(t *testing.T) {
+	words, _ := testMergeSentences(t)
+	w := words[10]
+	de := w.Localized("de").Sentences()
+	if len(de) != 1 || len(de[0].Translations) != 1 || de[0].Translations[0].Translation != "Ich schreibe." {
+		t.Errorf("unexpected localized sentences: %v", de)
+	}
+	if len(w.Sentences()[0].Translations) != 2 {
+		t.Error("localizing modified the original sentence")
+	}
+}
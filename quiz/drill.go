@@ -0,0 +1,380 @@
+package quiz
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+type Case uint8
+
+const (
+	Nom Case = iota
+	Gen
+	Dat
+	Acc
+	Inst
+	Prep
+)
+
+var (
+	caseNames = []string{"nominative", "genitive", "dative", "accusative", "instrumental", "prepositional"}
+	caseShort = []string{"nom", "gen", "dat", "acc", "inst", "prep"}
+)
+
+// Cases are all cases in order.
+var Cases = []Case{Nom, Gen, Dat, Acc, Inst, Prep}
+
+func (c Case) String() string { return caseNames[c] }
+func (c Case) Short() string  { return caseShort[c] }
+
+func (c Case) forms(d *openrussian.Declension) openrussian.StressedList {
+	if d == nil {
+		return nil
+	}
+	return []openrussian.StressedList{d.Nom, d.Gen, d.Dat, d.Acc, d.Inst, d.Prep}[c]
+}
+
+// ParseCases parses a comma separated list of (short) case names,
+// e.g.: inst,prepositional.
+func ParseCases(s string) ([]Case, error) {
+	var l []Case
+	for _, v := range split(s) {
+		found := false
+		for i := range caseNames {
+			if v == caseNames[i] || v == caseShort[i] {
+				l, found = append(l, Case(i)), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid case '%s', available: %s", v, strings.Join(caseShort, ", "))
+		}
+	}
+	return l, nil
+}
+
+type Person uint8
+
+const (
+	Sg1 Person = iota
+	Sg2
+	Sg3
+	Pl1
+	Pl2
+	Pl3
+)
+
+var (
+	personNames = []string{"я", "ты", "он/она/оно", "мы", "вы", "они"}
+	personShort = []string{"1sg", "2sg", "3sg", "1pl", "2pl", "3pl"}
+)
+
+// Persons are all persons in order.
+var Persons = []Person{Sg1, Sg2, Sg3, Pl1, Pl2, Pl3}
+
+func (p Person) String() string { return personNames[p] }
+func (p Person) Short() string  { return personShort[p] }
+
+// ParsePersons parses a comma separated list of persons, either pronouns
+// (я, ты, он, мы, ...) or 1sg, 2sg, 3sg, 1pl, 2pl, 3pl.
+func ParsePersons(s string) ([]Person, error) {
+	var l []Person
+	for _, v := range split(s) {
+		found := false
+		for i := range personNames {
+			if v == personShort[i] || strings.Contains("/"+personNames[i]+"/", "/"+v+"/") {
+				l, found = append(l, Person(i)), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid person '%s', available: %s", v, strings.Join(personShort, ", "))
+		}
+	}
+	return l, nil
+}
+
+func split(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// Slot identifies a form of a word: a case and number (nouns) or gender
+// (adjectives) or a person (verbs).
+type Slot struct {
+	Conjugation bool
+	Case        Case
+	// Number is sg or pl for nouns and m, f, n or pl for adjectives.
+	Number string
+	Person Person
+}
+
+var numberNames = map[string]string{
+	"sg": "singular",
+	"pl": "plural",
+	"m":  "masculine",
+	"f":  "feminine",
+	"n":  "neuter",
+}
+
+// String returns the slot as parsed by ParseSlot, e.g.: inst.pl or 1sg.
+func (s Slot) String() string {
+	if s.Conjugation {
+		return s.Person.Short()
+	}
+	return s.Case.Short() + "." + s.Number
+}
+
+func ParseSlot(str string) (Slot, error) {
+	if ix := strings.IndexByte(str, '.'); ix != -1 {
+		c, err := ParseCases(str[:ix])
+		if err != nil || len(c) != 1 {
+			return Slot{}, fmt.Errorf("invalid slot '%s'", str)
+		}
+		if _, ok := numberNames[str[ix+1:]]; !ok {
+			return Slot{}, fmt.Errorf("invalid slot '%s'", str)
+		}
+		return Slot{Case: c[0], Number: str[ix+1:]}, nil
+	}
+	p, err := ParsePersons(str)
+	if err != nil || len(p) != 1 {
+		return Slot{}, fmt.Errorf("invalid slot '%s'", str)
+	}
+	return Slot{Conjugation: true, Person: p[0]}, nil
+}
+
+// DrillOptions selects the forms to drill, empty Cases or Persons means all.
+type DrillOptions struct {
+	Declension  bool
+	Conjugation bool
+	Cases       []Case
+	Persons     []Person
+}
+
+// ParseDrillOptions parses the kind (decline, conjugate or both) and the
+// comma separated cases and persons of a drill.
+func ParseDrillOptions(kind, cases, persons string) (DrillOptions, error) {
+	var o DrillOptions
+	switch kind {
+	case "decline":
+		o.Declension = true
+	case "conjugate":
+		o.Conjugation = true
+	case "both":
+		o.Declension, o.Conjugation = true, true
+	default:
+		return o, fmt.Errorf("invalid kind '%s', available: decline, conjugate, both", kind)
+	}
+
+	var err error
+	if o.Cases, err = ParseCases(cases); err != nil {
+		return o, err
+	}
+	o.Persons, err = ParsePersons(persons)
+	return o, err
+}
+
+// Drill asks for a single form of a word.
+type Drill struct {
+	Word    *openrussian.Word
+	Slot    Slot
+	Answers openrussian.StressedList
+}
+
+func adjGender(a *openrussian.AdjInfo, number string) *openrussian.AdjGenderInfo {
+	switch number {
+	case "m":
+		return a.M
+	case "f":
+		return a.F
+	case "n":
+		return a.N
+	case "pl":
+		return a.Pl
+	}
+	return nil
+}
+
+func nonEmpty(l openrussian.StressedList) openrussian.StressedList {
+	n := make(openrussian.StressedList, 0, len(l))
+	for _, s := range l {
+		if s != "" && s != "-" && s != "—" {
+			n = append(n, s)
+		}
+	}
+	return n
+}
+
+// NewDrill returns the drill for the given slot of w, false if w does not have
+// that form.
+func NewDrill(w *openrussian.Word, s Slot) (*Drill, bool) {
+	var l openrussian.StressedList
+	switch {
+	case s.Conjugation:
+		if w.VerbInfo != nil && w.VerbInfo.Conjugation != nil {
+			l = openrussian.StressedList{w.VerbInfo.Conjugation.Forms()[s.Person]}
+		}
+	case w.NounInfo != nil:
+		n := w.NounInfo
+		switch {
+		case s.Number == "sg" && !n.PluralOnly:
+			l = s.Case.forms(n.Singular)
+		case s.Number == "pl" && !n.SingularOnly:
+			l = s.Case.forms(n.Plural)
+		}
+	case w.AdjInfo != nil:
+		if g := adjGender(w.AdjInfo, s.Number); g != nil {
+			l = s.Case.forms(g.Decl)
+		}
+	}
+
+	l = nonEmpty(l)
+	if len(l) == 0 {
+		return nil, false
+	}
+	return &Drill{Word: w, Slot: s, Answers: l}, true
+}
+
+func (d *Drill) Prompt() string {
+	if d.Slot.Conjugation {
+		return fmt.Sprintf("%s: %s …", d.Word.Stressed, d.Slot.Person)
+	}
+	return fmt.Sprintf("%s %s of %s", d.Slot.Case, numberNames[d.Slot.Number], d.Word.Stressed)
+}
+
+// Check grades answer ignoring stress, only an exact match is correct.
+func (d *Drill) Check(answer string) Grade {
+	exp := make([]string, len(d.Answers))
+	for i, s := range d.Answers {
+		exp[i] = string(s)
+	}
+	return GradeAnswer(answer, exp)
+}
+
+// Correct reports whether g is a correct answer to a drill.
+func (d *Drill) Correct(g Grade) bool { return g.Distance == 0 }
+
+func (o DrillOptions) slots(w *openrussian.Word) []Slot {
+	var numbers []string
+	switch {
+	case !o.Declension:
+	case w.NounInfo != nil:
+		numbers = []string{"sg", "pl"}
+	case w.AdjInfo != nil:
+		numbers = []string{"m", "f", "n", "pl"}
+	}
+
+	var l []Slot
+	cases := o.Cases
+	if len(cases) == 0 {
+		cases = Cases
+	}
+	for _, n := range numbers {
+		for _, c := range cases {
+			l = append(l, Slot{Case: c, Number: n})
+		}
+	}
+
+	if o.Conjugation && w.VerbInfo != nil {
+		persons := o.Persons
+		if len(persons) == 0 {
+			persons = Persons
+		}
+		for _, p := range persons {
+			l = append(l, Slot{Conjugation: true, Person: p})
+		}
+	}
+	return l
+}
+
+// Drills picks at most n random drills for the given words.
+func Drills(words []*openrussian.Word, o DrillOptions, n int, rnd *rand.Rand) []*Drill {
+	l := make([]*Drill, 0, n)
+	seen := 0
+	for _, w := range words {
+		for _, s := range o.slots(w) {
+			d, ok := NewDrill(w, s)
+			if !ok {
+				continue
+			}
+			seen++
+			if len(l) < n {
+				l = append(l, d)
+				continue
+			}
+			if ix := rnd.Intn(seen); ix < n {
+				l[ix] = d
+			}
+		}
+	}
+	rnd.Shuffle(len(l), func(i, j int) { l[i], l[j] = l[j], l[i] })
+	return l
+}
+
+// Drillable reports whether w is a noun, adjective or verb with the forms
+// of the kinds in o. It might still lack the selected cases or persons.
+func (o DrillOptions) Drillable(w *openrussian.Word) bool {
+	return o.Declension && (w.NounInfo != nil || w.AdjInfo != nil) ||
+		o.Conjugation && w.VerbInfo != nil && w.VerbInfo.Conjugation != nil
+}
+
+// randomTries is the amount of words RandomDrill tries before giving up.
+const randomTries = 100
+
+// RandomDrill picks a random drill for one of words without looking at the
+// others, false if none of randomTries random words had a selected form.
+func RandomDrill(words []*openrussian.Word, o DrillOptions, rnd *rand.Rand) (*Drill, bool) {
+	if len(words) == 0 {
+		return nil, false
+	}
+	for i := 0; i < randomTries; i++ {
+		w := words[rnd.Intn(len(words))]
+		slots := o.slots(w)
+		rnd.Shuffle(len(slots), func(i, j int) { slots[i], slots[j] = slots[j], slots[i] })
+		for _, s := range slots {
+			if d, ok := NewDrill(w, s); ok {
+				return d, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Key identifies a drill as <word id>:<slot>, see ParseDrill.
+func (d *Drill) Key() string {
+	return strconv.FormatUint(uint64(d.Word.ID), 10) + ":" + d.Slot.String()
+}
+
+// ParseDrill returns the drill identified by key.
+func ParseDrill(words openrussian.Words, key string) (*Drill, error) {
+	ix := strings.IndexByte(key, ':')
+	if ix == -1 {
+		return nil, fmt.Errorf("invalid drill '%s'", key)
+	}
+	id, err := strconv.ParseUint(key[:ix], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid drill '%s'", key)
+	}
+	w, ok := words[openrussian.ID(id)]
+	if !ok {
+		return nil, fmt.Errorf("no word with id %d", id)
+	}
+	s, err := ParseSlot(key[ix+1:])
+	if err != nil {
+		return nil, err
+	}
+	d, ok := NewDrill(w, s)
+	if !ok {
+		return nil, fmt.Errorf("%s has no %s form", w.Word, s)
+	}
+	return d, nil
+}
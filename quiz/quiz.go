@@ -0,0 +1,297 @@
+// Package quiz schedules flashcard reviews with the SM-2 algorithm and grades
+// typed answers by their levenshtein distance.
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/frizinak/goru/dict"
+	"github.com/frizinak/goru/openrussian"
+)
+
+type Direction uint8
+
+const (
+	// FromRussian shows the russian word and asks for a translation.
+	FromRussian Direction = iota
+	// ToRussian shows the translation and asks for the russian word.
+	ToRussian
+)
+
+func (d Direction) String() string {
+	if d == ToRussian {
+		return "to-ru"
+	}
+	return "ru"
+}
+
+// ParseDirections parses ru, to-ru or mixed.
+func ParseDirections(s string) ([]Direction, error) {
+	switch s {
+	case "ru":
+		return []Direction{FromRussian}, nil
+	case "to-ru":
+		return []Direction{ToRussian}, nil
+	case "mixed":
+		return []Direction{FromRussian, ToRussian}, nil
+	}
+	return nil, fmt.Errorf("invalid direction '%s', available: ru, to-ru, mixed", s)
+}
+
+// Quality of an answer as defined by SM-2:
+// 5 perfect, 4 a typo, 3 a few typos, 2 and less is a lapse.
+type Quality int
+
+const day = 24 * time.Hour
+
+type Card struct {
+	ID        openrussian.ID `json:"id"`
+	Direction Direction      `json:"dir"`
+	EF        float64        `json:"ef"`
+	Interval  int            `json:"interval"`
+	Reps      int            `json:"reps"`
+	Lapses    int            `json:"lapses"`
+	Due       time.Time      `json:"due"`
+	Last      time.Time      `json:"last"`
+}
+
+func NewCard(id openrussian.ID, dir Direction) *Card {
+	return &Card{ID: id, Direction: dir, EF: 2.5}
+}
+
+// New reports whether the card was never reviewed.
+func (c *Card) New() bool { return c.Last.IsZero() }
+
+// Review schedules the next review of c.
+func (c *Card) Review(q Quality, now time.Time) {
+	if q < 3 {
+		c.Reps = 0
+		c.Interval = 1
+		if !c.New() {
+			c.Lapses++
+		}
+	} else {
+		switch c.Reps {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.EF))
+		}
+		c.Reps++
+	}
+
+	d := float64(5 - q)
+	c.EF += 0.1 - d*(0.08+d*0.02)
+	if c.EF < 1.3 {
+		c.EF = 1.3
+	}
+
+	c.Last = now
+	c.Due = now.Add(time.Duration(c.Interval) * day)
+}
+
+type Grade struct {
+	Quality Quality
+	// Expected is the answer closest to what was given.
+	Expected string
+	// Distance is the levenshtein distance to Expected.
+	Distance int
+	// Edits between Expected and the answer, nil if there are none.
+	Edits dict.Edits
+}
+
+// Correct reports whether the answer counts as remembered.
+func (g Grade) Correct() bool { return g.Quality >= 3 }
+
+// normalize lowercases s and removes stress marks, parenthesized remarks and a
+// leading "to " (for english verbs).
+func normalize(s string) string {
+	s = strings.ToLower(openrussian.Stressed(s).Unstressed())
+	s = strings.ReplaceAll(s, "ё", "е")
+	for {
+		o, c := strings.IndexByte(s, '('), strings.IndexByte(s, ')')
+		if o == -1 || c < o {
+			break
+		}
+		s = s[:o] + s[c+1:]
+	}
+	s = strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || (unicode.IsPunct(r) && r != '-')
+	}), " ")
+	return strings.TrimPrefix(s, "to ")
+}
+
+// GradeAnswer grades answer against the closest of the expected answers.
+// Words of 3 letters or more allow for a typo, every 4 more letters for
+// another one.
+func GradeAnswer(answer string, expected []string) Grade {
+	answer = normalize(answer)
+	g := Grade{Distance: -1}
+	if len(expected) == 0 {
+		return g
+	}
+
+	for _, exp := range expected {
+		d := dict.Levenshtein([]rune(normalize(exp)), []rune(answer))
+		if g.Distance == -1 || d < g.Distance {
+			g.Expected, g.Distance = exp, d
+		}
+	}
+
+	exp := []rune(normalize(g.Expected))
+	allowed := 0
+	if len(exp) >= 3 {
+		allowed = (len(exp) + 3) / 4
+	}
+	switch {
+	case answer == "":
+		g.Quality = 0
+	case g.Distance == 0:
+		g.Quality = 5
+	case g.Distance == 1 && allowed >= 1:
+		g.Quality = 4
+	case g.Distance <= allowed:
+		g.Quality = 3
+	case g.Distance <= len(exp)/2:
+		g.Quality = 1
+	default:
+		g.Quality = 0
+	}
+
+	if answer != "" && g.Distance != 0 && g.Quality >= 1 {
+		g.Edits = dict.LevenshteinEdits(exp, []rune(answer))
+	}
+
+	return g
+}
+
+// Answers returns the accepted answers for w, w is expected to be localized.
+func Answers(w *openrussian.Word, dir Direction) []string {
+	if dir == ToRussian {
+		return []string{w.Stressed.String()}
+	}
+	var l []string
+	for _, t := range w.Translations {
+		l = append(l, t.Translation)
+		for _, a := range strings.FieldsFunc(t.Translation, func(r rune) bool { return r == ',' || r == ';' }) {
+			if a = strings.TrimSpace(a); a != "" {
+				l = append(l, a)
+			}
+		}
+	}
+	return l
+}
+
+// State holds all cards that were reviewed at least once.
+type State struct {
+	Cards map[string]*Card `json:"cards"`
+}
+
+func key(id openrussian.ID, dir Direction) string { return fmt.Sprintf("%d:%s", id, dir) }
+
+// LoadState reads the state at path, a missing file results in an empty
+// state.
+func LoadState(path string) (*State, error) {
+	s := &State{Cards: make(map[string]*Card)}
+	d, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(d, s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Cards == nil {
+		s.Cards = make(map[string]*Card)
+	}
+	return s, nil
+}
+
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	d, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, d, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Card returns the card for id and dir, a new one if it was never reviewed.
+func (s *State) Card(id openrussian.ID, dir Direction) *Card {
+	if c, ok := s.Cards[key(id, dir)]; ok {
+		return c
+	}
+	return NewCard(id, dir)
+}
+
+// Review schedules c and stores it in s.
+func (s *State) Review(c *Card, q Quality, now time.Time) {
+	c.Review(q, now)
+	s.Cards[key(c.ID, c.Direction)] = c
+}
+
+// Session returns the cards to review for the given words: due cards first,
+// most overdue first, followed by at most maxNew new cards in the order of
+// ids. Only one direction of a new word is included per session.
+// max limits the total, 0 is unlimited.
+func (s *State) Session(ids []openrussian.ID, dirs []Direction, now time.Time, max, maxNew int) []*Card {
+	var due, fresh []*Card
+	for _, id := range ids {
+		isNew := false
+		for _, dir := range dirs {
+			c := s.Card(id, dir)
+			switch {
+			case c.New():
+				if !isNew && len(fresh) < maxNew {
+					fresh = append(fresh, c)
+					isNew = true
+				}
+			case !c.Due.After(now):
+				due = append(due, c)
+			}
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].Due.Before(due[j].Due) })
+	cards := append(due, fresh...)
+	if max > 0 && len(cards) > max {
+		cards = cards[:max]
+	}
+	return cards
+}
+
+// NextDue returns the earliest due date after now of the cards of ids or
+// the zero time if there are none.
+func (s *State) NextDue(ids []openrussian.ID, now time.Time) time.Time {
+	var next time.Time
+	for _, id := range ids {
+		for _, dir := range []Direction{FromRussian, ToRussian} {
+			c, ok := s.Cards[key(id, dir)]
+			if !ok || !c.Due.After(now) {
+				continue
+			}
+			if next.IsZero() || c.Due.Before(next) {
+				next = c.Due
+			}
+		}
+	}
+	return next
+}
@@ -0,0 +1,239 @@
+package quiz
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+func TestGradeAnswer(t *testing.T) {
+	tests := []struct {
+		answer   string
+		expected []string
+		quality  Quality
+	}{
+		{"table", []string{"table", "desk"}, 5},
+		{"Desk", []string{"table", "desk"}, 5},
+		{"to write", []string{"write"}, 5},
+		{"писать", []string{"писа́ть"}, 5},
+		{"ещё", []string{"еще"}, 5},
+		{"castle", []string{"castle (building)"}, 5},
+		{"tabel", []string{"table"}, 3},
+		{"writ", []string{"write"}, 4},
+		{"здраствуйте", []string{"здра́вствуйте"}, 4},
+		{"chair", []string{"table"}, 0},
+		{"", []string{"table"}, 0},
+		{"да", []string{"я"}, 0},
+	}
+	for _, test := range tests {
+		g := GradeAnswer(test.answer, test.expected)
+		if g.Quality != test.quality {
+			t.Errorf("%q %v: expected quality %d got %d", test.answer, test.expected, test.quality, g.Quality)
+		}
+	}
+}
+
+func TestReview(t *testing.T) {
+	now := time.Now()
+	c := NewCard(1, FromRussian)
+	intervals := []int{1, 6, 16, 45}
+	for _, exp := range intervals {
+		c.Review(5, now)
+		if c.Interval != exp {
+			t.Fatalf("expected interval %d got %d", exp, c.Interval)
+		}
+	}
+
+	c.Review(1, now)
+	if c.Interval != 1 || c.Reps != 0 || c.Lapses != 1 {
+		t.Errorf("expected a lapse, got %+v", c)
+	}
+	if c.EF < 1.3 {
+		t.Errorf("ef below 1.3: %f", c.EF)
+	}
+	if !c.Due.Equal(now.Add(day)) {
+		t.Errorf("expected due tomorrow, got %s", c.Due)
+	}
+}
+
+func TestParseDrill(t *testing.T) {
+	for _, s := range []string{"inst.pl", "nom.f", "1sg", "3pl"} {
+		slot, err := ParseSlot(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if slot.String() != s {
+			t.Errorf("expected %s got %s", s, slot)
+		}
+	}
+	for _, s := range []string{"inst", "inst.x", "x.pl", "4pl", ""} {
+		if _, err := ParseSlot(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+
+	p, err := ParsePersons("я, Она,3pl")
+	if err != nil || len(p) != 3 || p[0] != Sg1 || p[1] != Sg3 || p[2] != Pl3 {
+		t.Errorf("unexpected persons %v %v", p, err)
+	}
+}
+
+func drillWords() openrussian.Words {
+	decl := func(nom, gen string) *openrussian.Declension {
+		return &openrussian.Declension{
+			Nom: openrussian.StressedList{openrussian.Stressed(nom)},
+			Gen: openrussian.StressedList{openrussian.Stressed(gen), "-"},
+		}
+	}
+	return openrussian.Words{
+		1: {ID: 1, Word: "стол", Stressed: "сто'л", NounInfo: &openrussian.NounInfo{
+			Singular: decl("сто'л", "стола'"),
+			Plural:   decl("столы'", "столо'в"),
+		}},
+		2: {ID: 2, Word: "ножницы", Stressed: "но'жницы", NounInfo: &openrussian.NounInfo{
+			PluralOnly: true,
+			Singular:   decl("—", "—"),
+			Plural:     decl("но'жницы", "но'жниц"),
+		}},
+		3: {ID: 3, Word: "новый", Stressed: "но'вый", AdjInfo: &openrussian.AdjInfo{
+			M: &openrussian.AdjGenderInfo{Decl: decl("но'вый", "но'вого")},
+			F: &openrussian.AdjGenderInfo{Decl: decl("но'вая", "но'вой")},
+		}},
+		4: {ID: 4, Word: "делать", Stressed: "де'лать", VerbInfo: &openrussian.VerbInfo{
+			Conjugation: &openrussian.Conjugation{Sg1: "де'лаю", Sg2: "де'лаешь", Pl3: "-"},
+		}},
+	}
+}
+
+func TestNewDrill(t *testing.T) {
+	words := drillWords()
+	tests := []struct {
+		id   openrussian.ID
+		slot string
+		exp  string
+	}{
+		{1, "gen.sg", "стола'"},
+		{1, "nom.pl", "столы'"},
+		{1, "inst.pl", ""},
+		{2, "nom.pl", "но'жницы"},
+		{2, "nom.sg", ""},
+		{3, "gen.f", "но'вой"},
+		{3, "nom.n", ""},
+		{4, "2sg", "де'лаешь"},
+		{4, "3pl", ""},
+		{4, "nom.sg", ""},
+		{1, "1sg", ""},
+	}
+	for _, test := range tests {
+		s, err := ParseSlot(test.slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, ok := NewDrill(words[test.id], s)
+		if test.exp == "" {
+			if ok {
+				t.Errorf("%d %s: expected no drill, got %v", test.id, test.slot, d.Answers)
+			}
+			continue
+		}
+		if !ok || len(d.Answers) != 1 || string(d.Answers[0]) != test.exp {
+			t.Errorf("%d %s: expected %s, got %v", test.id, test.slot, test.exp, d)
+			continue
+		}
+		if !d.Correct(d.Check(openrussian.Stressed(test.exp).Unstressed())) {
+			t.Errorf("%d %s: expected the unstressed answer to be correct", test.id, test.slot)
+		}
+	}
+
+	s, _ := ParseSlot("gen.sg")
+	if d, _ := NewDrill(words[1], s); len(d.Answers) != 1 {
+		t.Errorf("expected the dash to be dropped, got %v", d.Answers)
+	}
+}
+
+func TestDrills(t *testing.T) {
+	words := drillWords()
+	list := []*openrussian.Word{words[1], words[2], words[3], words[4]}
+	o, err := ParseDrillOptions("both", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := Drills(list, o, 100, rand.New(rand.NewSource(1)))
+	// стол: 4, ножницы: 2, новый: 4, делать: 2
+	if len(all) != 12 {
+		t.Errorf("expected 12 drills, got %d", len(all))
+	}
+
+	l := Drills(list, o, 5, rand.New(rand.NewSource(1)))
+	if len(l) != 5 {
+		t.Fatalf("expected 5 drills, got %d", len(l))
+	}
+	seen := make(map[string]struct{})
+	for _, d := range l {
+		k := d.Key()
+		if _, ok := seen[k]; ok {
+			t.Errorf("duplicate drill %s", k)
+		}
+		seen[k] = struct{}{}
+
+		p, err := ParseDrill(words, k)
+		if err != nil {
+			t.Errorf("%s: %s", k, err)
+			continue
+		}
+		if p.Word != d.Word || p.Slot != d.Slot || p.Key() != k {
+			t.Errorf("%s: round trip mismatch %s", k, p.Key())
+		}
+	}
+
+	o, _ = ParseDrillOptions("conjugate", "", "я")
+	if l := Drills(list, o, 10, rand.New(rand.NewSource(1))); len(l) != 1 || l[0].Key() != "4:1sg" {
+		t.Errorf("expected only 4:1sg, got %v", l)
+	}
+	o, _ = ParseDrillOptions("decline", "gen", "")
+	if l := Drills(list, o, 10, rand.New(rand.NewSource(1))); len(l) != 5 {
+		t.Errorf("expected 5 genitive drills, got %d", len(l))
+	}
+
+	for _, key := range []string{"", "1", "x:gen.sg", "9:gen.sg", "1:x", "2:nom.sg"} {
+		if _, err := ParseDrill(words, key); err == nil {
+			t.Errorf("%q: expected an error", key)
+		}
+	}
+}
+
+func TestRandomDrill(t *testing.T) {
+	words := drillWords()
+	list := []*openrussian.Word{words[1], words[2], words[3], words[4]}
+	rnd := rand.New(rand.NewSource(1))
+
+	o, _ := ParseDrillOptions("conjugate", "", "")
+	var drillable []*openrussian.Word
+	for _, w := range list {
+		if o.Drillable(w) {
+			drillable = append(drillable, w)
+		}
+	}
+	if len(drillable) != 1 || drillable[0] != words[4] {
+		t.Errorf("expected only делать to be drillable, got %v", drillable)
+	}
+
+	for i := 0; i < 20; i++ {
+		d, ok := RandomDrill(list, o, rnd)
+		if !ok || d.Word != words[4] || d.Slot.Person > Sg2 {
+			t.Fatalf("expected делать 1sg or 2sg, got %v", d)
+		}
+	}
+
+	o, _ = ParseDrillOptions("decline", "inst", "")
+	if d, ok := RandomDrill([]*openrussian.Word{words[1], words[4]}, o, rnd); ok {
+		t.Errorf("expected no drill, got %s", d.Key())
+	}
+	if _, ok := RandomDrill(nil, o, rnd); ok {
+		t.Error("expected no drill without words")
+	}
+}
@@ -0,0 +1,286 @@
+// Package termimg draws images inline in terminals using the kitty or sixel
+// graphics protocols or unicode half blocks.
+package termimg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+// Protocol is a way of drawing images in a terminal.
+type Protocol uint8
+
+const (
+	// Blocks draws the shape of an image with half blocks in the terminal's
+	// foreground color, it works in every terminal.
+	Blocks Protocol = iota
+	// Kitty uses the kitty graphics protocol.
+	Kitty
+	// Sixel uses DEC sixel graphics.
+	Sixel
+)
+
+// Protocols are the names accepted by ParseProtocol.
+var Protocols = []string{"auto", "kitty", "sixel", "blocks"}
+
+func (p Protocol) String() string {
+	switch p {
+	case Kitty:
+		return "kitty"
+	case Sixel:
+		return "sixel"
+	default:
+		return "blocks"
+	}
+}
+
+// ParseProtocol parses a protocol name, auto detects the protocol of the
+// current terminal.
+func ParseProtocol(s string) (Protocol, error) {
+	switch strings.ToLower(s) {
+	case "", "auto":
+		return Detect(), nil
+	case "kitty":
+		return Kitty, nil
+	case "sixel":
+		return Sixel, nil
+	case "blocks":
+		return Blocks, nil
+	}
+	return Blocks, fmt.Errorf("unknown graphics protocol '%s', available: %s", s, strings.Join(Protocols, ", "))
+}
+
+// Detect guesses the best protocol from the environment. Terminals can be
+// queried instead but that requires reading their reply from stdin.
+func Detect() Protocol {
+	term, prog := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		return Blocks
+	case os.Getenv("KITTY_WINDOW_ID") != "",
+		term == "xterm-kitty",
+		term == "xterm-ghostty",
+		prog == "ghostty",
+		prog == "WezTerm":
+		return Kitty
+	case strings.Contains(term, "sixel"),
+		strings.HasPrefix(term, "foot"),
+		strings.HasPrefix(term, "mlterm"),
+		strings.HasPrefix(term, "contour"):
+		return Sixel
+	}
+	return Blocks
+}
+
+// Write draws img to w followed by a newline. Blocks scales the image down to
+// at most cols columns, the graphics protocols leave sizing to the terminal.
+func Write(w io.Writer, img image.Image, p Protocol, cols int) error {
+	buf := bufio.NewWriter(w)
+	var err error
+	switch p {
+	case Kitty:
+		err = writeKitty(buf, img)
+	case Sixel:
+		err = writeSixel(buf, img)
+	default:
+		err = writeBlocks(buf, img, cols)
+	}
+	if err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	return buf.Flush()
+}
+
+const kittyChunk = 4096
+
+func writeKitty(w *bufio.Writer, img image.Image) error {
+	b := bytes.NewBuffer(nil)
+	if err := png.Encode(b, img); err != nil {
+		return err
+	}
+	data := base64.StdEncoding.EncodeToString(b.Bytes())
+	for i := 0; i < len(data); i += kittyChunk {
+		end, more := i+kittyChunk, 1
+		if end >= len(data) {
+			end, more = len(data), 0
+		}
+		if i == 0 {
+			fmt.Fprintf(w, "\033_Ga=T,f=100,q=2,m=%d;", more)
+		} else {
+			fmt.Fprintf(w, "\033_Gm=%d;", more)
+		}
+		w.WriteString(data[i:end])
+		w.WriteString("\033\\")
+	}
+	return nil
+}
+
+// opaque reports whether the pixel at x, y is mostly opaque.
+func opaque(img image.Image, x, y int) bool {
+	_, _, _, a := img.At(x, y).RGBA()
+	return a >= 0x8000
+}
+
+// palette maps the (non alpha-premultiplied) color of a pixel to a 6x6x6
+// color cube.
+func palette(img image.Image, x, y int) int {
+	c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+	q := func(v uint8) int { return (int(v)*5 + 127) / 255 }
+	return q(c.R)*36 + q(c.G)*6 + q(c.B)
+}
+
+func writeSixel(w *bufio.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// P2=1 leaves transparent pixels untouched.
+	fmt.Fprintf(w, "\033P0;1;0q\"1;1;%d;%d", width, height)
+
+	used := make(map[int]struct{})
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if opaque(img, x, y) {
+				used[palette(img, x, y)] = struct{}{}
+			}
+		}
+	}
+	for c := 0; c < 216; c++ {
+		if _, ok := used[c]; ok {
+			fmt.Fprintf(w, "#%d;2;%d;%d;%d", c, c/36*20, c/6%6*20, c%6*20)
+		}
+	}
+
+	band := make(map[int][]byte)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 6 {
+		for c := range band {
+			delete(band, c)
+		}
+		for x := 0; x < width; x++ {
+			for i := 0; i < 6 && y+i < bounds.Max.Y; i++ {
+				px := bounds.Min.X + x
+				if !opaque(img, px, y+i) {
+					continue
+				}
+				c := palette(img, px, y+i)
+				if band[c] == nil {
+					band[c] = make([]byte, width)
+				}
+				band[c][x] |= 1 << i
+			}
+		}
+
+		first := true
+		for c := 0; c < 216; c++ {
+			row, ok := band[c]
+			if !ok {
+				continue
+			}
+			if !first {
+				w.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(w, "#%d", c)
+			writeSixelRow(w, row)
+		}
+		w.WriteByte('-')
+	}
+
+	w.WriteString("\033\\")
+	return nil
+}
+
+// writeSixelRow run-length encodes a row of sixels, trailing blanks are
+// omitted.
+func writeSixelRow(w *bufio.Writer, row []byte) {
+	end := len(row)
+	for end > 0 && row[end-1] == 0 {
+		end--
+	}
+	for i := 0; i < end; {
+		n := 1
+		for i+n < end && row[i+n] == row[i] {
+			n++
+		}
+		c := row[i] + 63
+		switch {
+		case n > 3:
+			fmt.Fprintf(w, "!%d%c", n, c)
+		default:
+			for j := 0; j < n; j++ {
+				w.WriteByte(c)
+			}
+		}
+		i += n
+	}
+}
+
+var halfBlocks = [4]string{" ", "▀", "▄", "█"}
+
+func writeBlocks(w *bufio.Writer, img image.Image, cols int) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	scale := 1.0
+	if cols > 0 && width > cols {
+		scale = float64(width) / float64(cols)
+		width = cols
+		height = int(float64(height) / scale)
+	}
+
+	// on reports whether most of the source pixels covered by the scaled
+	// pixel at x, y are opaque.
+	on := func(x, y int) bool {
+		if y >= height {
+			return false
+		}
+		if scale == 1 {
+			return opaque(img, bounds.Min.X+x, bounds.Min.Y+y)
+		}
+		x0, y0 := int(float64(x)*scale), int(float64(y)*scale)
+		x1, y1 := int(float64(x+1)*scale), int(float64(y+1)*scale)
+		var n, total int
+		for sy := y0; sy < y1; sy++ {
+			for sx := x0; sx < x1; sx++ {
+				total++
+				if opaque(img, bounds.Min.X+sx, bounds.Min.Y+sy) {
+					n++
+				}
+			}
+		}
+		return n*2 >= total && n != 0
+	}
+
+	lines := make([]string, 0, (height+1)/2)
+	for y := 0; y < height; y += 2 {
+		line := make([]string, width)
+		for x := 0; x < width; x++ {
+			var i int
+			if on(x, y) {
+				i |= 1
+			}
+			if on(x, y+1) {
+				i |= 2
+			}
+			line[x] = halfBlocks[i]
+		}
+		l := strings.TrimRight(strings.Join(line, ""), " ")
+		lines = append(lines, l)
+	}
+
+	for len(lines) != 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) != 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	_, err := w.WriteString(strings.Join(lines, "\n"))
+	return err
+}
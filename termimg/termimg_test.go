@@ -0,0 +1,70 @@
+package termimg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func testImage(rows ...string) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, r := range rows {
+		for x, c := range r {
+			if c == '#' {
+				img.SetNRGBA(x, y, color.NRGBA{255, 255, 255, 255})
+			}
+		}
+	}
+	return img
+}
+
+func TestBlocks(t *testing.T) {
+	img := testImage(
+		"....",
+		"....",
+		"#.#.",
+		"##..",
+		"#...",
+	)
+
+	buf := bytes.NewBuffer(nil)
+	if err := Write(buf, img, Blocks, 0); err != nil {
+		t.Fatal(err)
+	}
+	exp := "█▄▀\n▀\n"
+	if got := buf.String(); got != exp {
+		t.Errorf("exp: %q got: %q", exp, got)
+	}
+
+	buf.Reset()
+	if err := Write(buf, testImage("####", "####"), Blocks, 2); err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := "▀▀\n", buf.String(); got != exp {
+		t.Errorf("scaled: exp: %q got: %q", exp, got)
+	}
+}
+
+func TestSixel(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	if err := Write(buf, testImage("#####.#", "#####.."), Sixel, 0); err != nil {
+		t.Fatal(err)
+	}
+	exp := "\033P0;1;0q\"1;1;7;2#215;2;100;100;100#215!5B?@-\033\\\n"
+	if got := buf.String(); got != exp {
+		t.Errorf("exp: %q got: %q", exp, got)
+	}
+}
+
+func TestKitty(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	if err := Write(buf, image.NewNRGBA(image.Rect(0, 0, 200, 200)), Kitty, 0); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if !strings.HasPrefix(got, "\033_Ga=T,f=100,q=2,m=") || !strings.HasSuffix(got, "\033\\\n") {
+		t.Errorf("unexpected kitty output: %q", got)
+	}
+}
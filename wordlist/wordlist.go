@@ -0,0 +1,170 @@
+// Package wordlist stores named lists of words by their openrussian.ID so
+// they survive dataset refreshes.
+package wordlist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+const ext = ".json"
+
+// Default is the name of the list used when none is given.
+const Default = "default"
+
+type Entry struct {
+	ID openrussian.ID `json:"id"`
+	// Word is informational, the id is what identifies the word.
+	Word  string    `json:"word"`
+	Added time.Time `json:"added"`
+}
+
+type List struct {
+	Name    string  `json:"-"`
+	Entries []Entry `json:"entries"`
+}
+
+func (l *List) index(id openrussian.ID) int {
+	for i, e := range l.Entries {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *List) Has(id openrussian.ID) bool { return l.index(id) != -1 }
+
+// Add adds w to the list, returning false if it already was in it.
+func (l *List) Add(w *openrussian.Word) bool {
+	if l.Has(w.ID) {
+		return false
+	}
+	l.Entries = append(l.Entries, Entry{ID: w.ID, Word: w.Stressed.String(), Added: time.Now()})
+	return true
+}
+
+// Remove removes id from the list, returning false if it was not in it.
+func (l *List) Remove(id openrussian.ID) bool {
+	ix := l.index(id)
+	if ix == -1 {
+		return false
+	}
+	l.Entries = append(l.Entries[:ix], l.Entries[ix+1:]...)
+	return true
+}
+
+// Words resolves the entries in the order they were added.
+// Entries no longer in words are returned as missing.
+func (l *List) Words(words openrussian.Words) (found []*openrussian.Word, missing []Entry) {
+	found = make([]*openrussian.Word, 0, len(l.Entries))
+	for _, e := range l.Entries {
+		if w, ok := words[e.ID]; ok {
+			found = append(found, w)
+			continue
+		}
+		missing = append(missing, e)
+	}
+	return
+}
+
+// Store keeps each list as a json file in a directory.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store { return &Store{dir: dir} }
+
+// ValidName reports whether name can be used as a list name.
+func ValidName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid list name '%s'", name)
+	}
+	return nil
+}
+
+func (s *Store) path(name string) string { return filepath.Join(s.dir, name+ext) }
+
+// Names returns the sorted names of all lists.
+func (s *Store) Names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ext) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(e.Name(), ext))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load reads the list with the given name, a list that does not exist yet is
+// returned empty.
+func (s *Store) Load(name string) (*List, error) {
+	if err := ValidName(name); err != nil {
+		return nil, err
+	}
+	l := &List{Name: name}
+	d, err := os.ReadFile(s.path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(d, l); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path(name), err)
+	}
+	return l, nil
+}
+
+// Exists reports whether a list with the given name was saved.
+func (s *Store) Exists(name string) bool {
+	_, err := os.Stat(s.path(name))
+	return err == nil
+}
+
+func (s *Store) Save(l *List) error {
+	if err := ValidName(l.Name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	d, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := s.path(l.Name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, d, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *Store) Delete(name string) error {
+	if err := ValidName(name); err != nil {
+		return err
+	}
+	err := os.Remove(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no list named '%s'", name)
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package wordlist
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/frizinak/goru/openrussian"
+)
+
+func TestValidName(t *testing.T) {
+	tests := map[string]bool{
+		"reading":    true,
+		"A1 verbs":   true,
+		"":           false,
+		".":          false,
+		"..":         false,
+		".hidden":    false,
+		"../escape":  false,
+		"a/b":        false,
+		`a\b`:        false,
+		"ok.list":    true,
+		"стол-слова": true,
+	}
+	for name, exp := range tests {
+		if got := ValidName(name) == nil; got != exp {
+			t.Errorf("ValidName(%q): expected valid=%t", name, exp)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	words := openrussian.Words{
+		1: {ID: 1, Word: "стол", Stressed: "сто'л"},
+		2: {ID: 2, Word: "дом", Stressed: "до'м"},
+	}
+	l := &List{Name: "test"}
+	if !l.Add(words[1]) || !l.Add(words[2]) {
+		t.Fatal("expected new words to be added")
+	}
+	if l.Add(words[1]) {
+		t.Error("expected a duplicate add to be a no-op")
+	}
+	if len(l.Entries) != 2 || l.Entries[0].Word != "сто́л" {
+		t.Errorf("unexpected entries: %+v", l.Entries)
+	}
+
+	l.Entries = append(l.Entries, Entry{ID: 3, Word: "removed"})
+	found, missing := l.Words(words)
+	if len(found) != 2 || found[0] != words[1] || found[1] != words[2] {
+		t.Errorf("expected both words in order, got %v", found)
+	}
+	if len(missing) != 1 || missing[0].ID != 3 {
+		t.Errorf("expected id 3 to be missing, got %v", missing)
+	}
+
+	if !l.Remove(1) || l.Remove(1) {
+		t.Error("expected remove to succeed once")
+	}
+	if l.Has(1) || !l.Has(2) {
+		t.Errorf("unexpected entries after remove: %+v", l.Entries)
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "lists")
+	s := NewStore(dir)
+
+	names, err := s.Names()
+	if err != nil || len(names) != 0 {
+		t.Fatalf("expected no lists in a missing dir, got %v %v", names, err)
+	}
+
+	l, err := s.Load("reading")
+	if err != nil || len(l.Entries) != 0 || l.Name != "reading" {
+		t.Fatalf("expected a new empty list, got %+v %v", l, err)
+	}
+	if s.Exists("reading") {
+		t.Error("an unsaved list should not exist")
+	}
+
+	l.Add(&openrussian.Word{ID: 5, Stressed: "окно'"})
+	if err := s.Save(l); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(&List{Name: "a1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "reading.json.tmp")); !os.IsNotExist(err) {
+		t.Errorf("expected the temp file to be renamed, got %v", err)
+	}
+
+	loaded, err := s.Load("reading")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Entries) != 1 || loaded.Entries[0].ID != 5 || loaded.Entries[0].Word != "окно́" ||
+		!loaded.Entries[0].Added.Equal(l.Entries[0].Added) {
+		t.Errorf("round trip mismatch: %+v vs %+v", loaded.Entries, l.Entries)
+	}
+
+	names, err = s.Names()
+	if err != nil || !reflect.DeepEqual(names, []string{"a1", "reading"}) {
+		t.Errorf("unexpected names: %v %v", names, err)
+	}
+
+	if _, err := s.Load("../reading"); err == nil {
+		t.Error("expected Load to reject an invalid name")
+	}
+	if err := s.Save(&List{Name: "../x"}); err == nil {
+		t.Error("expected Save to reject an invalid name")
+	}
+
+	if err := s.Delete("a1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("a1"); err == nil {
+		t.Error("expected an error deleting a missing list")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load("broken"); err == nil {
+		t.Error("expected an error for invalid json")
+	}
+}